@@ -0,0 +1,86 @@
+package construct
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAPIModuleMatchesRoutes(t *testing.T) {
+	tests := []struct {
+		resource string
+		fields   []string
+		requests map[string]string // Request of the API module to the route serving it
+		absent   []string          // Requests the API module does not make
+	}{
+		{
+			resource: "Post",
+			fields:   []string{"title:string"},
+			requests: map[string]string{
+				"postsApi = {": "",
+				"apiClient.get('/posts', { params: toQuery(params) })": `router.GET("/posts", c.List)`,
+				"apiClient.get('/posts/all')":                          `router.GET("/posts/all", c.ListAll)`,
+				"apiClient.get('/posts/' + id)":                        `router.GET("/posts/:id", c.Get)`,
+				"apiClient.post('/posts', data)":                       `router.POST("/posts", c.Create)`,
+				"apiClient.put('/posts/' + id, data)":                  `router.PUT("/posts/:id", c.Update)`,
+				"apiClient.delete('/posts/' + id)":                     `router.DELETE("/posts/:id", c.Delete)`,
+				"request<Paginated<Post>>":                             "",
+			},
+			absent: []string{"getBy"},
+		},
+		{
+			resource: "BlogCategory",
+			fields:   []string{"name:string", "url_slug:slug(name)"},
+			requests: map[string]string{
+				"blogCategoriesApi = {":              "",
+				"getByURLSlug: (urlSlug: string) =>": "",
+				"apiClient.get('/blog-categories/by-url-slug/' + encodeURIComponent(urlSlug))": `router.GET("/blog-categories/by-url-slug/:url_slug", c.GetByURLSlug)`,
+				"'Failed to fetch blog category'":                                              "",
+			},
+		},
+		{
+			resource: "Person",
+			fields:   []string{"name:string", "profile_photo:image"},
+			requests: map[string]string{
+				"peopleApi = {": "",
+				"apiClient.post(`/people/${id}/${field}`, form": `router.POST("/people/:id/profile-photo", c.UploadProfilePhoto)`,
+				"apiClient.delete(`/people/${id}/${field}`)":    `router.DELETE("/people/:id/profile-photo", c.RemoveProfilePhoto)`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			data, err := NewTemplateData(tt.resource, tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			api := string(builtinFile(t, "api.ts", "frontend/api.ts", data).Content)
+			controller := string(builtinFile(t, "controller.go", "base/controller.tmpl", NewBackendTemplateData("example.com/app", data)).Content)
+
+			for request, route := range tt.requests {
+				if !strings.Contains(api, request) {
+					t.Errorf("api.ts has no %s:\n%s", request, api)
+				}
+				if route != "" && !strings.Contains(controller, route) {
+					t.Errorf("controller.go does not route %s with %s", request, route)
+				}
+			}
+			for _, request := range tt.absent {
+				if strings.Contains(api, request) {
+					t.Errorf("api.ts has %s:\n%s", request, api)
+				}
+			}
+		})
+	}
+}
+
+func TestAttachmentFieldPaths(t *testing.T) {
+	// The form names an attachment in the upload path as the controller does
+	data, err := NewTemplateData("Person", []string{"name:string", "profile_photo:image"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	modal := string(builtinFile(t, "PeopleAddModal.vue", "frontend/AddModal.vue", data).Content)
+	if want := "store.saveAttachment(saved.id, 'profile-photo', event.data.profile_photo"; !strings.Contains(modal, want) {
+		t.Errorf("AddModal.vue has no %s:\n%s", want, modal)
+	}
+}
@@ -0,0 +1,78 @@
+package construct
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Image and file fields are stored as attachments through the project's
+// ActiveStorage and uploaded after the record is saved:
+//
+//	avatar:image            JPEG, PNG, GIF or WebP image up to 5 MB
+//	document:file:max=20    any file up to 20 MB
+//
+// The max modifier of an attachment is its size limit in megabytes.
+
+// attachmentTypes lists the field types stored as attachments
+var attachmentTypes = []string{"image", "file"}
+
+// attachmentType is the TypeScript type of an attachment in responses
+const attachmentType = "{ id: number; filename: string; size: number; url: string }"
+
+// imageMimeTypes are the MIME types accepted by image fields
+var imageMimeTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// defaultMaxSizes are the size limits in megabytes of attachments without a
+// max modifier
+var defaultMaxSizes = map[string]int{"image": 5, "file": 10}
+
+// setAttachment makes the field an attachment of the given type
+func (f *TemplateField) setAttachment(fieldType string) {
+	f.IsAttachment = true
+	f.TypeScriptType = attachmentType
+	f.ZeroValue = "undefined"
+	f.InitialValue = "undefined"
+	f.ItemValue = "undefined"
+	f.Sortable = false
+	f.MaxSize = defaultMaxSizes[fieldType]
+	if fieldType == "image" {
+		f.Accept = imageMimeTypes
+		f.AcceptLabel = "JPEG, PNG, GIF or WebP"
+	}
+}
+
+// AcceptList returns the accepted MIME types as a file input accept value
+func (f TemplateField) AcceptList() string {
+	return strings.Join(f.Accept, ",")
+}
+
+// acceptLiterals returns the accepted MIME types as TypeScript literals
+func (f TemplateField) acceptLiterals() []string {
+	var literals []string
+	for _, t := range f.Accept {
+		literals = append(literals, tsLiteral("string", t))
+	}
+	return literals
+}
+
+// attachmentZodSchema validates the File picked for an attachment; null
+// removes the current file and undefined keeps it
+func attachmentZodSchema(f TemplateField) string {
+	var b strings.Builder
+	b.WriteString("z.instanceof(File)")
+	b.WriteString(".refine((file) => file.size <= " + strconv.Itoa(f.MaxSize) + " * 1024 * 1024, '" + f.Label + " must be at most " + strconv.Itoa(f.MaxSize) + " MB')")
+	if len(f.Accept) > 0 {
+		b.WriteString(".refine((file) => [" + strings.Join(f.acceptLiterals(), ", ") + "].includes(file.type), '" + f.Label + " must be a " + f.AcceptLabel + " " + f.Type + "')")
+	}
+	b.WriteString(".nullable().optional()")
+	return b.String()
+}
+
+// attachmentGoNames returns the Go names of the handlers an attachment adds,
+// which the routes refer to
+func (f TemplateField) attachmentGoNames() []string {
+	if !f.IsAttachment {
+		return nil
+	}
+	return []string{"Upload" + f.FieldName, "Remove" + f.FieldName}
+}
@@ -0,0 +1,89 @@
+package construct
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAttachmentFields(t *testing.T) {
+	tests := []struct {
+		arg     string
+		maxSize int
+		accept  string
+		zod     string
+		goNames []string
+	}{
+		{
+			arg:     "avatar:image",
+			maxSize: 5,
+			accept:  "image/jpeg,image/png,image/gif,image/webp",
+			zod:     "z.instanceof(File).refine((file) => file.size <= 5 * 1024 * 1024, 'Avatar must be at most 5 MB').refine((file) => ['image/jpeg', 'image/png', 'image/gif', 'image/webp'].includes(file.type), 'Avatar must be a JPEG, PNG, GIF or WebP image').nullable().optional()",
+			goNames: []string{"UploadAvatar", "RemoveAvatar"},
+		},
+		{
+			arg:     "cover_photo:image:max=2",
+			maxSize: 2,
+			accept:  "image/jpeg,image/png,image/gif,image/webp",
+			zod:     "z.instanceof(File).refine((file) => file.size <= 2 * 1024 * 1024, 'Cover photo must be at most 2 MB').refine((file) => ['image/jpeg', 'image/png', 'image/gif', 'image/webp'].includes(file.type), 'Cover photo must be a JPEG, PNG, GIF or WebP image').nullable().optional()",
+			goNames: []string{"UploadCoverPhoto", "RemoveCoverPhoto"},
+		},
+		{
+			arg:     "document:file",
+			maxSize: 10,
+			zod:     "z.instanceof(File).refine((file) => file.size <= 10 * 1024 * 1024, 'Document must be at most 10 MB').nullable().optional()",
+			goNames: []string{"UploadDocument", "RemoveDocument"},
+		},
+		{
+			arg:     "document:file:max=20",
+			maxSize: 20,
+			zod:     "z.instanceof(File).refine((file) => file.size <= 20 * 1024 * 1024, 'Document must be at most 20 MB').nullable().optional()",
+			goNames: []string{"UploadDocument", "RemoveDocument"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			f, err := parseField(tt.arg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !f.IsAttachment || f.MaxSize != tt.maxSize || f.AcceptList() != tt.accept {
+				t.Errorf("attachment %v up to %d MB accepting %q, want up to %d MB accepting %q", f.IsAttachment, f.MaxSize, f.AcceptList(), tt.maxSize, tt.accept)
+			}
+			if f.TypeScriptType != attachmentType || f.Sortable {
+				t.Errorf("TypeScript type %q, sortable %v, want %q and not sortable", f.TypeScriptType, f.Sortable, attachmentType)
+			}
+			if f.GORMTag != "" || f.BindingTag != "" {
+				t.Errorf("gorm %q, binding %q, want neither", f.GORMTag, f.BindingTag)
+			}
+			if f.ZodSchema != tt.zod {
+				t.Errorf("zod schema\n%s\nwant\n%s", f.ZodSchema, tt.zod)
+			}
+			if got := f.attachmentGoNames(); !reflect.DeepEqual(got, tt.goNames) {
+				t.Errorf("Go names %q, want %q", got, tt.goNames)
+			}
+		})
+	}
+
+	if f, err := parseField("title:string"); err != nil || f.attachmentGoNames() != nil {
+		t.Errorf("title:string has attachment Go names %q, %v", f.attachmentGoNames(), err)
+	}
+}
+
+func TestAttachmentFieldErrors(t *testing.T) {
+	tests := []struct {
+		arg, message, suggestion string
+	}{
+		{"avatar:image:required", `modifier "required" does not apply to image fields`, "attachments take only max=N, their size limit in MB"},
+		{"avatar:image:unique", `modifier "unique" does not apply to image fields`, "attachments take only max=N, their size limit in MB"},
+		{"document:file:min=1", `modifier "min" does not apply to file fields`, "attachments take only max=N, their size limit in MB"},
+		{"document:file:max=0", "size limit must be at least 1 MB", "e.g. max=5"},
+		{"document:file:max=big", `modifier "max" needs a non-negative integer`, "e.g. max=255"},
+	}
+	for _, tt := range tests {
+		_, err := parseField(tt.arg)
+		fe, ok := err.(*FieldError)
+		if !ok || fe.Message != tt.message || fe.Suggestion != tt.suggestion {
+			t.Errorf("parseField(%q) = %v, want %q (%s)", tt.arg, err, tt.message, tt.suggestion)
+		}
+	}
+}
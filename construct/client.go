@@ -0,0 +1,833 @@
+package construct
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/base-go/mamba"
+)
+
+var clientGenCmd = &mamba.Command{
+	Use:   "client:gen [module...]",
+	Short: "Generate a TypeScript client from the swagger docs",
+	Long: `Generate typed request functions and models from the API docs.
+
+The spec is read from docs/swagger.json, which construct dev writes with
+swag, or docs/openapi.json. Every endpoint gets a function in the
+api/client.ts of the Vue module named after the first segment of its path,
+e.g. GET /posts/{id} becomes getPostsById in vue/app/posts/api/client.ts,
+along with the models its requests and responses use. Requests throw an
+ApiError like the API modules construct g generates. The client.ts files
+are overwritten every time, edits to them are lost.
+
+Without a module every module of the spec is generated. Run it again after
+changing the Go request and response structs to keep the client in sync.
+
+Examples:
+  construct client:gen
+  construct client:gen posts auth          # Only these modules
+  construct client:gen --spec api.json     # Another spec file
+  construct client:gen --diff              # Show what would change`,
+	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+
+		opts, err := generateOptions(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		spec, _ := cmd.Flags().GetString("spec")
+		runClientGen(spec, args, opts)
+	},
+}
+
+func init() {
+	clientGenCmd.Flags().String("spec", "", "swagger or OpenAPI file to read, docs/swagger.json by default")
+	clientGenCmd.Flags().Bool("dry-run", false, "list the files that would be created, modified or skipped")
+	clientGenCmd.Flags().Bool("diff", false, "show a unified diff of every change (implies --dry-run)")
+	clientGenCmd.Flags().Bool("force", false, "overwrite utils/requests.ts if it differs from the generated output, client.ts files always are")
+	clientGenCmd.Flags().Bool("skip-existing", false, "leave utils/requests.ts untouched if it exists")
+}
+
+// specPaths are where client:gen looks for the API docs, in order
+var specPaths = []string{"docs/swagger.json", "docs/openapi.json", "openapi.json"}
+
+func runClientGen(spec string, modules []string, opts GenerateOptions) {
+	printBanner()
+
+	root, err := findProjectRoot()
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	files, err := clientFiles(root, spec, modules)
+	if err == nil {
+		err = writeFiles(root, files, opts)
+	}
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !opts.preview() {
+		fmt.Println()
+		fmt.Println("🎉 Client generated")
+	}
+}
+
+// clientFiles renders the client of each module of the spec, and the
+// helpers its requests share
+func clientFiles(root, spec string, modules []string) ([]*generatedFile, error) {
+	path, err := findSpec(root, spec)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	doc, err := parseAPISpec(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", displayPath(root, path), err)
+	}
+
+	grouped := doc.moduleOperations()
+	if len(modules) == 0 {
+		for module := range grouped {
+			modules = append(modules, module)
+		}
+		sort.Strings(modules)
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("%s has no endpoints", displayPath(root, path))
+	}
+
+	source := displayPath(root, path)
+	version := templateVersion(string(content))
+	var files []*generatedFile
+	for _, module := range modules {
+		operations, ok := grouped[module]
+		if !ok {
+			var names []string
+			for name := range grouped {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("%s has no %s endpoints (%s)", source, module, suggestion(module, names))
+		}
+		file := newGeneratedFile(root, filepath.Join(root, "vue", "app", module, "api", "client.ts"), []byte(doc.renderClient(source, operations)))
+		file.Template, file.TemplateVersion = source, version
+		file.Derived = true
+		files = append(files, file)
+	}
+
+	requests, err := requestsFile(root, &TemplateData{})
+	if err != nil {
+		return nil, err
+	}
+	return append(files, requests), nil
+}
+
+// findSpec returns the spec file to read, the given one or the first of
+// specPaths that exists
+func findSpec(root, spec string) (string, error) {
+	if spec != "" {
+		if !filepath.IsAbs(spec) {
+			spec = filepath.Join(root, spec)
+		}
+		if !fileExists(spec) {
+			return "", fmt.Errorf("%s does not exist", displayPath(root, spec))
+		}
+		return spec, nil
+	}
+	for _, rel := range specPaths {
+		if path := filepath.Join(root, filepath.FromSlash(rel)); fileExists(path) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no API docs found in %s, run construct dev or swag init to write them", strings.Join(specPaths, " or "))
+}
+
+// apiSpec is a swagger 2.0 or OpenAPI 3 document, as far as the client needs
+type apiSpec struct {
+	Paths       map[string]map[string]json.RawMessage `json:"paths"`
+	Definitions map[string]*apiSchema                 `json:"definitions"` // swagger
+	Components  struct {
+		Schemas map[string]*apiSchema `json:"schemas"` // OpenAPI
+	} `json:"components"`
+
+	names map[string]string // TypeScript names of the schemas, by $ref
+}
+
+type apiOperation struct {
+	OperationID string          `json:"operationId"`
+	Summary     string          `json:"summary"`
+	Parameters  []*apiParameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool                 `json:"required"`
+		Content  map[string]*apiMedia `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Schema  *apiSchema           `json:"schema"`  // swagger
+		Content map[string]*apiMedia `json:"content"` // OpenAPI
+	} `json:"responses"`
+
+	method string
+	path   string
+}
+
+type apiParameter struct {
+	Name        string     `json:"name"`
+	In          string     `json:"in"`
+	Required    bool       `json:"required"`
+	Description string     `json:"description"`
+	Schema      *apiSchema `json:"schema"` // Body parameters and OpenAPI
+	apiSchema              // Other swagger parameters describe their type inline
+}
+
+type apiMedia struct {
+	Schema *apiSchema `json:"schema"`
+}
+
+type apiSchema struct {
+	Ref                  string                `json:"$ref"`
+	Type                 schemaType            `json:"type"`
+	Format               string                `json:"format"`
+	Description          string                `json:"description"`
+	Enum                 []any                 `json:"enum"`
+	Items                *apiSchema            `json:"items"`
+	Properties           map[string]*apiSchema `json:"properties"`
+	Required             []string              `json:"required"`
+	AdditionalProperties json.RawMessage       `json:"additionalProperties"`
+	AllOf                []*apiSchema          `json:"allOf"`
+	OneOf                []*apiSchema          `json:"oneOf"`
+	AnyOf                []*apiSchema          `json:"anyOf"`
+	Nullable             bool                  `json:"nullable"`
+	XNullable            bool                  `json:"x-nullable"`
+}
+
+// schemaType is the type of a schema, which OpenAPI 3.1 may give as a list
+// such as ["string", "null"]
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = schemaType{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// httpMethods are the operations of a path item the client calls
+var httpMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// parseAPISpec reads a swagger or OpenAPI document
+func parseAPISpec(content []byte) (*apiSpec, error) {
+	var doc apiSpec
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Definitions == nil {
+		doc.Definitions = doc.Components.Schemas
+	}
+	doc.names = schemaNames(doc.Definitions)
+	return &doc, nil
+}
+
+// moduleOperations groups the operations of the spec by the first segment
+// of their path, which names the Vue module of the resource
+func (doc *apiSpec) moduleOperations() map[string][]*apiOperation {
+	grouped := map[string][]*apiOperation{}
+	for path, item := range doc.Paths {
+		module, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+		if module == "" || strings.HasPrefix(module, "{") {
+			continue
+		}
+		var shared []*apiParameter
+		if raw, ok := item["parameters"]; ok {
+			json.Unmarshal(raw, &shared)
+		}
+		for _, method := range httpMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			op := &apiOperation{}
+			if err := json.Unmarshal(raw, op); err != nil {
+				continue
+			}
+			op.method, op.path = method, path
+			op.Parameters = append(append([]*apiParameter{}, shared...), op.Parameters...)
+			grouped[module] = append(grouped[module], op)
+		}
+	}
+	for _, operations := range grouped {
+		sort.Slice(operations, func(i, j int) bool {
+			if operations[i].path != operations[j].path {
+				return operations[i].path < operations[j].path
+			}
+			return methodOrder(operations[i].method) < methodOrder(operations[j].method)
+		})
+	}
+	return grouped
+}
+
+func methodOrder(method string) int {
+	for i, m := range httpMethods {
+		if m == method {
+			return i
+		}
+	}
+	return len(httpMethods)
+}
+
+// schemaNames gives each schema of the spec a TypeScript name, e.g.
+// models.CreatePostRequest becomes CreatePostRequest, keeping the package
+// when two schemas would share a name
+func schemaNames(schemas map[string]*apiSchema) map[string]string {
+	keys := make([]string, 0, len(schemas))
+	for key := range schemas {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	short := map[string][]string{}
+	for _, key := range keys {
+		name := typeName(key, false)
+		short[name] = append(short[name], key)
+	}
+	names := map[string]string{}
+	for _, key := range keys {
+		names[key] = typeName(key, len(short[typeName(key, false)]) > 1)
+	}
+	return names
+}
+
+// typeName turns a schema key into a TypeScript type name, leaving out its
+// Go package unless qualified, e.g. types.PaginatedResponse-models_Post
+// becomes PaginatedResponseModelsPost
+func typeName(key string, qualified bool) string {
+	if !qualified && strings.Contains(key, ".") {
+		pkg, _, _ := strings.Cut(key, ".")
+		key = strings.TrimPrefix(key, pkg+".")
+	}
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "Schema" + name
+	}
+	return name
+}
+
+// refKey returns the key of the schema a $ref points to
+func refKey(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// renderClient renders the models and request functions of a module
+func (doc *apiSpec) renderClient(source string, operations []*apiOperation) string {
+	var functions strings.Builder
+	used := map[string]bool{}
+	names := map[string]int{}
+	sendsForm := false
+	for _, op := range operations {
+		function, form := doc.renderOperation(op, names, used)
+		functions.WriteString("\n")
+		functions.WriteString(function)
+		sendsForm = sendsForm || form
+	}
+	if sendsForm {
+		functions.WriteString(toFormDataFunction)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by construct client:gen from %s, do not edit: run it again to update\n", source)
+	b.WriteString("\n")
+	b.WriteString("import { apiClient } from '~/core/api/client'\n")
+	if strings.Contains(functions.String(), "toQuery(") {
+		b.WriteString("import { request, toQuery } from '~/utils/requests'\n")
+	} else {
+		b.WriteString("import { request } from '~/utils/requests'\n")
+	}
+	for _, key := range doc.closure(used) {
+		b.WriteString("\n")
+		b.WriteString(doc.renderModel(key))
+	}
+	b.WriteString(functions.String())
+	return b.String()
+}
+
+// closure returns the schemas the used ones refer to, themselves included,
+// sorted by name
+func (doc *apiSpec) closure(used map[string]bool) []string {
+	queue := make([]string, 0, len(used))
+	for key := range used {
+		queue = append(queue, key)
+	}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		doc.walkRefs(doc.Definitions[key], func(ref string) {
+			if !used[ref] {
+				used[ref] = true
+				queue = append(queue, ref)
+			}
+		})
+	}
+
+	keys := make([]string, 0, len(used))
+	for key := range used {
+		if _, ok := doc.Definitions[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return doc.names[keys[i]] < doc.names[keys[j]] })
+	return keys
+}
+
+// walkRefs calls visit with the key of every schema s refers to
+func (doc *apiSpec) walkRefs(s *apiSchema, visit func(key string)) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		visit(refKey(s.Ref))
+	}
+	doc.walkRefs(s.Items, visit)
+	for _, p := range s.Properties {
+		doc.walkRefs(p, visit)
+	}
+	doc.walkRefs(s.additional(), visit)
+	for _, list := range [][]*apiSchema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, sub := range list {
+			doc.walkRefs(sub, visit)
+		}
+	}
+}
+
+// additional returns the schema of the additional properties of an object,
+// or nil when they are not described
+func (s *apiSchema) additional() *apiSchema {
+	if len(s.AdditionalProperties) == 0 || s.AdditionalProperties[0] != '{' {
+		return nil
+	}
+	var additional apiSchema
+	if json.Unmarshal(s.AdditionalProperties, &additional) != nil {
+		return nil
+	}
+	return &additional
+}
+
+// renderModel renders a schema of the spec as an interface, or as a type
+// when it is not an object
+func (doc *apiSpec) renderModel(key string) string {
+	s := doc.Definitions[key]
+	var b strings.Builder
+	if s.Description != "" {
+		fmt.Fprintf(&b, "// %s\n", oneLine(s.Description))
+	}
+	name := doc.names[key]
+	if s.Ref == "" && len(s.AllOf) == 0 && len(s.OneOf) == 0 && len(s.AnyOf) == 0 && s.additional() == nil && (s.is("object") || len(s.Properties) > 0) {
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+		for _, prop := range doc.properties(s, func(string) {}) {
+			b.WriteString("  " + prop + "\n")
+		}
+		b.WriteString("}\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "export type %s = %s\n", name, doc.tsType(s, func(string) {}))
+	return b.String()
+}
+
+// properties renders the properties of an object, sorted by name, each
+// followed by its description
+func (doc *apiSpec) properties(s *apiSchema, use func(key string)) []string {
+	required := map[string]bool{}
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	props := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		props = append(props, name)
+	}
+	sort.Strings(props)
+
+	lines := make([]string, len(props))
+	for i, name := range props {
+		p := s.Properties[name]
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		lines[i] = propertyName(name) + optional + ": " + doc.tsType(p, use)
+		if p.Description != "" {
+			lines[i] += " // " + oneLine(p.Description)
+		}
+	}
+	return lines
+}
+
+// fields renders the properties of an object without their descriptions,
+// for object types written on one line
+func (doc *apiSpec) fields(s *apiSchema, use func(key string)) []string {
+	lines := doc.properties(s, use)
+	for i, line := range lines {
+		lines[i], _, _ = strings.Cut(line, " // ")
+	}
+	return lines
+}
+
+// tsType returns the TypeScript type of a schema, calling use with the key
+// of every schema it refers to
+func (doc *apiSpec) tsType(s *apiSchema, use func(key string)) string {
+	if s == nil {
+		return "unknown"
+	}
+	t := doc.baseType(s, use)
+	if s.Nullable || s.XNullable || containsString(s.Type, "null") {
+		t += " | null"
+	}
+	return t
+}
+
+func (doc *apiSpec) baseType(s *apiSchema, use func(key string)) string {
+	if s.Ref != "" {
+		key := refKey(s.Ref)
+		use(key)
+		if name, ok := doc.names[key]; ok {
+			return name
+		}
+		return "unknown"
+	}
+	if len(s.AllOf) > 0 {
+		return doc.joinTypes(s.AllOf, " & ", use)
+	}
+	if len(s.OneOf) > 0 {
+		return doc.joinTypes(s.OneOf, " | ", use)
+	}
+	if len(s.AnyOf) > 0 {
+		return doc.joinTypes(s.AnyOf, " | ", use)
+	}
+	if len(s.Enum) > 0 {
+		literals := make([]string, len(s.Enum))
+		for i, value := range s.Enum {
+			literals[i] = enumLiteral(value)
+		}
+		return strings.Join(literals, " | ")
+	}
+
+	switch {
+	case s.is("string"):
+		if s.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case s.is("file"):
+		return "Blob"
+	case s.is("integer"), s.is("number"):
+		return "number"
+	case s.is("boolean"):
+		return "boolean"
+	case s.is("array"):
+		item := doc.tsType(s.Items, use)
+		if strings.ContainsAny(item, " ") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	}
+
+	if len(s.Properties) > 0 {
+		return objectType(doc.fields(s, use))
+	}
+	if additional := s.additional(); additional != nil {
+		return "Record<string, " + doc.tsType(additional, use) + ">"
+	}
+	if s.is("object") {
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+// joinTypes joins the types of schemas with an operator, such as the
+// members of an allOf
+func (doc *apiSpec) joinTypes(schemas []*apiSchema, operator string, use func(key string)) string {
+	types := make([]string, len(schemas))
+	for i, s := range schemas {
+		types[i] = doc.tsType(s, use)
+		if strings.ContainsAny(types[i], "|&") {
+			types[i] = "(" + types[i] + ")"
+		}
+	}
+	return strings.Join(types, operator)
+}
+
+// is reports whether the schema has the given type
+func (s *apiSchema) is(name string) bool {
+	return containsString(s.Type, name)
+}
+
+// enumLiteral returns the TypeScript literal of an enum value
+func enumLiteral(value any) string {
+	switch v := value.(type) {
+	case string:
+		return tsLiteral("string", v)
+	case nil:
+		return "null"
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+
+// propertyName returns a property name as it is written in an interface,
+// quoted unless it is an identifier
+func propertyName(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return tsLiteral("string", name)
+}
+
+// reservedWords cannot name the parameters of the request functions
+var reservedWords = []string{"break", "case", "catch", "class", "const", "continue", "default", "delete", "do", "else", "enum", "export", "extends", "false", "finally", "for", "function", "if", "import", "in", "instanceof", "new", "null", "return", "super", "switch", "this", "throw", "true", "try", "typeof", "var", "void", "while", "with", "body", "form", "query"}
+
+// variableName turns a parameter name into a variable, e.g. post_id becomes
+// postId
+func variableName(name string) string {
+	v := toCamelCase(name)
+	if v == "" || unicode.IsDigit(rune(v[0])) {
+		v = "param" + toPascalCase(name)
+	}
+	if containsString(reservedWords, v) {
+		v += "Param"
+	}
+	return v
+}
+
+// oneLine joins the lines of a description
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// functionName names the request function of an operation, after its
+// operationId or else its method and path, e.g. GET /posts/{id} becomes
+// getPostsById and GET /posts/by-slug/{slug} becomes getPostsBySlug
+func (op *apiOperation) functionName() string {
+	if op.OperationID != "" {
+		return variableName(op.OperationID)
+	}
+	var b strings.Builder
+	b.WriteString(op.method)
+	previous := ""
+	for _, segment := range strings.Split(strings.Trim(op.path, "/"), "/") {
+		if param, ok := strings.CutPrefix(segment, "{"); ok {
+			param = strings.TrimSuffix(param, "}")
+			if previous != "by-"+param {
+				b.WriteString("By" + toPascalCase(param))
+			}
+		} else {
+			b.WriteString(toPascalCase(segment))
+		}
+		previous = segment
+	}
+	return b.String()
+}
+
+// renderOperation renders the request function of an operation, reporting
+// whether it sends a form. Its path parameters come first, then the body or
+// form and then the query.
+func (doc *apiSpec) renderOperation(op *apiOperation, names map[string]int, used map[string]bool) (string, bool) {
+	use := func(key string) { used[key] = true }
+
+	name := op.functionName()
+	if names[name]++; names[name] > 1 {
+		name += strconv.Itoa(names[name])
+	}
+
+	var args, query, form []string
+	url := op.path
+	body, bodyRequired := "", false
+	queryRequired, formRequired := false, false
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			v := variableName(p.Name)
+			url = strings.Replace(url, "{"+p.Name+"}", "${encodeURIComponent(String("+v+"))}", 1)
+			args = append(args, v+": "+doc.parameterType(p, use))
+		case "query":
+			query = append(query, doc.parameterField(p, use))
+			queryRequired = queryRequired || p.Required
+		case "formData":
+			form = append(form, doc.parameterField(p, use))
+			formRequired = formRequired || p.Required
+		case "body":
+			body, bodyRequired = doc.tsType(p.Schema, use), p.Required
+		}
+	}
+	if op.RequestBody != nil {
+		contentType, media := mediaSchema(op.RequestBody.Content)
+		switch {
+		case media == nil:
+		case strings.HasPrefix(contentType, "multipart/") || contentType == "application/x-www-form-urlencoded":
+			form = append(form, doc.fields(media, use)...)
+			formRequired = op.RequestBody.Required
+		default:
+			body, bodyRequired = doc.tsType(media, use), op.RequestBody.Required
+		}
+	}
+
+	// axios takes the data of a request before its options, except for GET
+	// and DELETE which only take options
+	var data string
+	var options []string
+	switch {
+	case len(form) > 0:
+		data = "toFormData(form)"
+	case body != "":
+		data = "body"
+	}
+	if len(query) > 0 {
+		options = append(options, "params: toQuery(query)")
+	}
+	call := "`" + url + "`"
+	if op.method == "get" || op.method == "delete" {
+		if data != "" {
+			options = append(options, "data: "+data)
+		}
+	} else if data != "" || len(options) > 0 {
+		if data == "" {
+			data = "undefined"
+		}
+		call += ", " + data
+	}
+	if len(options) > 0 {
+		call += ", { " + strings.Join(options, ", ") + " }"
+	}
+
+	switch {
+	case body != "" && bodyRequired:
+		args = append(args, "body: "+body)
+	case body != "":
+		args = append(args, "body?: "+body)
+	case len(form) > 0 && formRequired:
+		args = append(args, "form: "+objectType(form))
+	case len(form) > 0:
+		args = append(args, "form: "+objectType(form)+" = {}")
+	}
+	if len(query) > 0 {
+		arg := "query: " + objectType(query)
+		if !queryRequired {
+			arg += " = {}"
+		}
+		args = append(args, arg)
+	}
+
+	var b strings.Builder
+	if op.Summary != "" {
+		fmt.Fprintf(&b, "// %s\n", oneLine(op.Summary))
+	}
+	fmt.Fprintf(&b, "export function %s(%s) {\n", name, strings.Join(args, ", "))
+	fmt.Fprintf(&b, "  return request<%s>(() => apiClient.%s(%s), '%s %s failed')\n", doc.responseType(op, use), op.method, call, strings.ToUpper(op.method), op.path)
+	b.WriteString("}\n")
+	return b.String(), len(form) > 0
+}
+
+// toFormDataFunction is rendered after the requests when one sends a form
+const toFormDataFunction = `
+// Form data of the fields that are set, for the requests sending files
+function toFormData(fields: object): FormData {
+  const form = new FormData()
+  for (const [key, value] of Object.entries(fields)) {
+    if (value !== undefined && value !== null) {
+      form.append(key, value instanceof Blob ? value : String(value))
+    }
+  }
+  return form
+}
+`
+
+// objectType writes fields as an object type on one line
+func objectType(fields []string) string {
+	if len(fields) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(fields, "; ") + " }"
+}
+
+// parameterField renders a query or form parameter as an object field
+func (doc *apiSpec) parameterField(p *apiParameter, use func(key string)) string {
+	optional := "?"
+	if p.Required {
+		optional = ""
+	}
+	return propertyName(p.Name) + optional + ": " + doc.parameterType(p, use)
+}
+
+// parameterType returns the TypeScript type of a parameter, described by
+// its schema or inline
+func (doc *apiSpec) parameterType(p *apiParameter, use func(key string)) string {
+	if p.Schema != nil {
+		return doc.tsType(p.Schema, use)
+	}
+	return doc.tsType(&p.apiSchema, use)
+}
+
+// responseType returns the type of the body of the first successful
+// response of an operation, or void when it has none
+func (doc *apiSpec) responseType(op *apiOperation, use func(key string)) string {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		response := op.Responses[code]
+		if response.Schema != nil {
+			return doc.tsType(response.Schema, use)
+		}
+		if _, media := mediaSchema(response.Content); media != nil {
+			return doc.tsType(media, use)
+		}
+	}
+	return "void"
+}
+
+// mediaSchema picks the JSON content of a request or response body, or else
+// the first, returning its type and schema
+func mediaSchema(content map[string]*apiMedia) (string, *apiSchema) {
+	if media, ok := content["application/json"]; ok && media != nil {
+		return "application/json", media.Schema
+	}
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+	sort.Strings(types)
+	for _, contentType := range types {
+		if media := content[contentType]; media != nil {
+			return contentType, media.Schema
+		}
+	}
+	return "", nil
+}
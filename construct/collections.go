@@ -0,0 +1,86 @@
+package construct
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Array and JSON fields are stored in a single column with GORM's JSON
+// serializer:
+//
+//	tags:[]string       []string, edited as tags
+//	scores:[]int        []int, edited as tags checked to be whole numbers
+//	meta:json           map[string]any, edited as JSON text
+//
+// min and max bound the number of items of an array. The form converts a
+// json field to and from its text with the helpers in utils/json.ts.
+
+// jsonTypes lists the field types stored with the JSON serializer
+var jsonTypes = []string{"[]string", "[]int", "json"}
+
+// jsonGoTypes maps the Go type of a JSON field back to its field type
+var jsonGoTypes = map[string]string{
+	"[]string":       "[]string",
+	"[]int":          "[]int",
+	"map[string]any": "json",
+}
+
+// setJSONType makes the field an array or JSON object of the given type
+func (f *TemplateField) setJSONType(fieldType string) {
+	f.IsJSON = true
+	f.Sortable = false
+	switch fieldType {
+	case "[]string":
+		f.ItemValue = "[...(item." + f.Name + " ?? [])]"
+	case "[]int":
+		// The tags input edits the numbers as strings
+		f.ItemValue = "(item." + f.Name + " ?? []).map(String)"
+		f.SubmitValue = "event.data." + f.Name + "?.map(Number)"
+	case "json":
+		f.InitialValue = "''"
+		f.ItemValue = "toJSONInput(item." + f.Name + ")"
+		f.SubmitValue = "fromJSONInput(event.data." + f.Name + ")"
+	}
+}
+
+// jsonZodSchema validates the items of an array or the text of a JSON object
+func jsonZodSchema(f TemplateField) string {
+	var b strings.Builder
+	switch f.Type {
+	case "[]string":
+		fmt.Fprintf(&b, "z.array(z.string().trim().min(1, '%s cannot be blank'))", f.Label)
+	case "[]int":
+		fmt.Fprintf(&b, "z.array(z.string().regex(/^-?\\d+$/, '%s must be whole numbers'))", f.Label)
+	case "json":
+		b.WriteString("z.string()")
+		if f.Required {
+			fmt.Fprintf(&b, ".min(1, '%s is required')", f.Label)
+		}
+		fmt.Fprintf(&b, ".refine(isJSONObject, '%s must be a JSON object')", f.Label)
+		return b.String()
+	}
+	items := strings.ToLower(f.Label)
+	if f.Min != "" {
+		fmt.Fprintf(&b, ".min(%s, 'Add at least %s %s')", f.Min, f.Min, items)
+	} else if f.Required {
+		fmt.Fprintf(&b, ".min(1, 'Add at least one %s')", strings.ToLower(humanize(singularize(f.Name))))
+	}
+	if f.Max != "" {
+		fmt.Fprintf(&b, ".max(%s, 'At most %s %s')", f.Max, f.Max, items)
+	}
+	return b.String()
+}
+
+// jsonFile renders utils/json.ts, which the json fields of every resource
+// share, or returns nil when the fields have none. Like utils/dates.ts it
+// is left in place when a resource is destroyed.
+func jsonFile(root string, data *TemplateData) (*generatedFile, error) {
+	for _, f := range data.Fields {
+		if f.Type == "json" {
+			t := projectTemplate(root, filepath.Join(root, "vue", "app", "utils", "json.ts"), "frontend/json.ts")
+			return renderFileTemplate(root, t, data)
+		}
+	}
+	return nil, nil
+}
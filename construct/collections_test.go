@@ -0,0 +1,83 @@
+package construct
+
+import "testing"
+
+func TestJSONFields(t *testing.T) {
+	tests := []struct {
+		arg                   string
+		goType, tsType        string
+		initial, item, submit string
+		zod, gorm, binding    string
+	}{
+		{
+			arg: "tags:[]string", goType: "[]string", tsType: "string[]",
+			initial: "[]", item: "[...(item.tags ?? [])]", submit: "event.data.tags",
+			zod:  "z.array(z.string().trim().min(1, 'Tags cannot be blank')).optional()",
+			gorm: "serializer:json", binding: "omitempty,dive,required",
+		},
+		{
+			arg: "tags:[]string:required", goType: "[]string", tsType: "string[]",
+			initial: "[]", item: "[...(item.tags ?? [])]", submit: "event.data.tags",
+			zod:  "z.array(z.string().trim().min(1, 'Tags cannot be blank')).min(1, 'Add at least one tag')",
+			gorm: "serializer:json;not null", binding: "required,min=1,dive,required",
+		},
+		{
+			// Arrays are empty rather than null, so ? changes nothing
+			arg: "tags:[]string?", goType: "[]string", tsType: "string[]",
+			initial: "[]", item: "[...(item.tags ?? [])]", submit: "event.data.tags",
+			zod:  "z.array(z.string().trim().min(1, 'Tags cannot be blank')).optional()",
+			gorm: "serializer:json", binding: "omitempty,dive,required",
+		},
+		{
+			arg: "scores:[]int:min=2:max=5", goType: "[]int", tsType: "number[]",
+			initial: "[]", item: "(item.scores ?? []).map(String)", submit: "event.data.scores?.map(Number)",
+			zod:  `z.array(z.string().regex(/^-?\d+$/, 'Scores must be whole numbers')).min(2, 'Add at least 2 scores').max(5, 'At most 5 scores').optional()`,
+			gorm: "serializer:json", binding: "omitempty,min=2,max=5",
+		},
+		{
+			arg: "meta:json", goType: "map[string]any", tsType: "Record<string, unknown>",
+			initial: "''", item: "toJSONInput(item.meta)", submit: "fromJSONInput(event.data.meta)",
+			zod:  "z.string().refine(isJSONObject, 'Meta must be a JSON object').optional()",
+			gorm: "serializer:json",
+		},
+		{
+			arg: "meta:json:required", goType: "map[string]any", tsType: "Record<string, unknown>",
+			initial: "''", item: "toJSONInput(item.meta)", submit: "fromJSONInput(event.data.meta)",
+			zod:  "z.string().min(1, 'Meta is required').refine(isJSONObject, 'Meta must be a JSON object')",
+			gorm: "serializer:json;not null", binding: "required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			f, b := backendField(t, tt.arg)
+			if !f.IsJSON || f.Sortable || b.Type != tt.goType || f.TypeScriptType != tt.tsType {
+				t.Errorf("json %v sortable %v typed %s / %s, want %s / %s", f.IsJSON, f.Sortable, b.Type, f.TypeScriptType, tt.goType, tt.tsType)
+			}
+			if f.InitialValue != tt.initial || f.ItemValue != tt.item || f.SubmitValue != tt.submit {
+				t.Errorf("form values %q, %q, %q, want %q, %q, %q", f.InitialValue, f.ItemValue, f.SubmitValue, tt.initial, tt.item, tt.submit)
+			}
+			if f.ZodSchema != tt.zod {
+				t.Errorf("zod schema\n%s\nwant\n%s", f.ZodSchema, tt.zod)
+			}
+			if f.GORMTag != tt.gorm || f.BindingTag != tt.binding {
+				t.Errorf("gorm %q, binding %q, want %q, %q", f.GORMTag, f.BindingTag, tt.gorm, tt.binding)
+			}
+		})
+	}
+}
+
+func TestJSONFieldErrors(t *testing.T) {
+	for _, tt := range []struct {
+		arg, message string
+	}{
+		{"meta:json:max=3", `modifier "max" does not apply to json fields`},
+		{"meta:json:min=1", `modifier "min" does not apply to json fields`},
+		{"meta:json=x", `modifier "default" does not apply to json fields`},
+		{"tags:[]string:default=a", `modifier "default" does not apply to []string fields`},
+	} {
+		_, err := parseField(tt.arg)
+		if fe, ok := err.(*FieldError); !ok || fe.Message != tt.message {
+			t.Errorf("parseField(%q) = %v, want %q", tt.arg, err, tt.message)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package construct
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Date, datetime and time fields are typed on the backend and edited with
+// native pickers:
+//
+//	published_on:date     types.DateTime in a date column, e.g. 2024-05-01
+//	starts_at:datetime    types.DateTime, sent as an ISO 8601 UTC time
+//	opens_at:time         string in a time column, e.g. 09:30
+//
+// Responses carry ISO 8601 strings, which the helpers in utils/dates.ts
+// convert to and from the values of the form inputs and format for the
+// user's locale. The list endpoint filters date and datetime fields by
+// range with name_from and name_to query parameters, see filters.go.
+
+// dateTypes lists the field types edited with a date or time picker
+var dateTypes = []string{"date", "datetime", "time"}
+
+// dateFormats describe the form value of each date type, the pattern it
+// matches, the input that edits it and an example default
+var dateFormats = map[string]struct {
+	pattern, description, input, example string
+}{
+	"date":     {`\d{4}-\d{2}-\d{2}`, "a date (YYYY-MM-DD)", "date", `default=2024-12-31`},
+	"datetime": {`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}`, "a date and time (YYYY-MM-DDTHH:MM)", "datetime-local", `default="2024-12-31T09:30"`},
+	"time":     {`\d{2}:\d{2}`, "a time (HH:MM)", "time", `default="09:30"`},
+}
+
+// setDateType makes the field a date of the given type, converted between
+// the API and its form input by the helpers in utils/dates.ts
+func (f *TemplateField) setDateType(fieldType string) {
+	f.IsDate = true
+	f.ZeroValue = "undefined"
+	f.InitialValue = "undefined"
+	switch fieldType {
+	case "date":
+		f.ItemValue = "toDateInput(item." + f.Name + ")"
+		f.SubmitValue = "fromDateInput(event.data." + f.Name + ")"
+	case "datetime":
+		f.ItemValue = "toDateTimeInput(item." + f.Name + ")"
+		f.SubmitValue = "fromDateTimeInput(event.data." + f.Name + ")"
+	case "time":
+		f.ItemValue = "toTimeInput(item." + f.Name + ")"
+	}
+}
+
+// DateInput returns the type of the input that edits the field
+func (f TemplateField) DateInput() string {
+	return dateFormats[f.Type].input
+}
+
+// checkDateDefault checks that a default value is in the form value format
+func checkDateDefault(fieldType, value string) error {
+	format := dateFormats[fieldType]
+	if !regexp.MustCompile(`^` + format.pattern + `$`).MatchString(value) {
+		return fmt.Errorf("default %q is not %s", value, format.description)
+	}
+	return nil
+}
+
+// dateZodSchema validates the value of a date input, which is empty when
+// the input is cleared
+func dateZodSchema(f TemplateField) string {
+	format := dateFormats[f.Type]
+	noun, _, _ := strings.Cut(format.description, " (")
+	if f.Required {
+		return fmt.Sprintf("z.string().min(1, '%s is required').regex(/^%s$/, '%s must be %s')", f.Label, format.pattern, f.Label, noun)
+	}
+	return fmt.Sprintf("z.string().regex(/^(%s)?$/, '%s must be %s')", format.pattern, f.Label, noun)
+}
+
+// datesFile renders utils/dates.ts, which the date fields of every resource
+// share, or returns nil when the fields have no dates. It is not part of
+// frontendTemplates, so destroying a resource leaves it in place.
+func datesFile(root string, data *TemplateData) (*generatedFile, error) {
+	for _, f := range data.Fields {
+		if f.IsDate {
+			t := projectTemplate(root, filepath.Join(root, "vue", "app", "utils", "dates.ts"), "frontend/dates.ts")
+			return renderFileTemplate(root, t, data)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,102 @@
+package construct
+
+import "testing"
+
+func TestDateFields(t *testing.T) {
+	tests := []struct {
+		arg                      string
+		input, item, submit, zod string
+		gorm, binding            string
+		filter                   string
+	}{
+		{
+			arg:    "published_on:date",
+			input:  "date",
+			item:   "toDateInput(item.published_on)",
+			submit: "fromDateInput(event.data.published_on)",
+			zod:    `z.string().regex(/^(\d{4}-\d{2}-\d{2})?$/, 'Published on must be a date').optional()`,
+			gorm:   "type:date",
+			filter: "date",
+		},
+		{
+			arg:     "starts_at:datetime:required",
+			input:   "datetime-local",
+			item:    "toDateTimeInput(item.starts_at)",
+			submit:  "fromDateTimeInput(event.data.starts_at)",
+			zod:     `z.string().min(1, 'Starts at is required').regex(/^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}$/, 'Starts at must be a date and time')`,
+			gorm:    "not null",
+			binding: "required",
+			filter:  "date",
+		},
+		{
+			arg:     "opens_at:time",
+			input:   "time",
+			item:    "toTimeInput(item.opens_at)",
+			submit:  "event.data.opens_at",
+			zod:     `z.string().regex(/^(\d{2}:\d{2})?$/, 'Opens at must be a time').optional()`,
+			gorm:    "type:time",
+			binding: "omitempty,datetime=15:04",
+		},
+		{
+			arg:    "ends_on:date?",
+			input:  "date",
+			item:   "toDateInput(item.ends_on)",
+			submit: "fromDateInput(event.data.ends_on) || null",
+			zod:    `z.string().regex(/^(\d{4}-\d{2}-\d{2})?$/, 'Ends on must be a date').nullable().optional()`,
+			gorm:   "type:date",
+			filter: "date",
+		},
+		{
+			arg:    "due_on:date=2024-12-31",
+			input:  "date",
+			item:   "toDateInput(item.due_on)",
+			submit: "fromDateInput(event.data.due_on)",
+			zod:    `z.string().regex(/^(\d{4}-\d{2}-\d{2})?$/, 'Due on must be a date').default('2024-12-31')`,
+			gorm:   "type:date;not null;default:'2024-12-31'",
+			filter: "date",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			f, err := parseField(tt.arg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !f.IsDate || f.TypeScriptType != "string" || f.DateInput() != tt.input {
+				t.Errorf("date %v typed %s edited with %q, want a string edited with %q", f.IsDate, f.TypeScriptType, f.DateInput(), tt.input)
+			}
+			if f.ItemValue != tt.item || f.SubmitValue != tt.submit {
+				t.Errorf("form value %q, submitted as %q, want %q and %q", f.ItemValue, f.SubmitValue, tt.item, tt.submit)
+			}
+			if f.ZodSchema != tt.zod {
+				t.Errorf("zod schema\n%s\nwant\n%s", f.ZodSchema, tt.zod)
+			}
+			if f.GORMTag != tt.gorm || f.BindingTag != tt.binding || f.Filter() != tt.filter {
+				t.Errorf("gorm %q, binding %q, filter %q, want %q, %q, %q", f.GORMTag, f.BindingTag, f.Filter(), tt.gorm, tt.binding, tt.filter)
+			}
+		})
+	}
+}
+
+func TestCheckDateDefault(t *testing.T) {
+	tests := []struct {
+		fieldType, value string
+		valid            bool
+	}{
+		{"date", "2024-12-31", true},
+		{"date", "2024-12-31T09:30", false},
+		{"date", "31/12/2024", false},
+		{"date", "", false},
+		{"datetime", "2024-12-31T09:30", true},
+		{"datetime", "2024-12-31 09:30", false},
+		{"datetime", "2024-12-31", false},
+		{"time", "09:30", true},
+		{"time", "9:30", false},
+		{"time", "09:30:00", false},
+	}
+	for _, tt := range tests {
+		if err := checkDateDefault(tt.fieldType, tt.value); (err == nil) != tt.valid {
+			t.Errorf("checkDateDefault(%s, %q) = %v, want valid %v", tt.fieldType, tt.value, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package construct
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Money and decimal fields hold exact numbers:
+//
+//	price:money(USD)      2 decimal places, formatted as dollars
+//	rate:decimal(10,4)    10 digits, 4 of them after the decimal point
+//
+// Both are a models.Decimal in Go, a string backed type in a decimal
+// column, and strings in JSON and TypeScript so that no value is ever
+// rounded through a float. money defaults to USD and decimal to (10,2).
+
+// decimalTypes lists the field types holding exact decimal numbers
+var decimalTypes = []string{"money", "decimal"}
+
+// moneyPrecision is the number of digits of a money column
+const moneyPrecision = 19
+
+// currencyScales are the decimal places of currencies without 2 of them
+var currencyScales = map[string]int{
+	"BHD": 3, "CLP": 0, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+var currencyPattern = regexp.MustCompile(`^[A-Za-z]{3}$`)
+
+// parseDecimalArgs parses the "(USD)" of a money type or the "(10,4)" of a
+// decimal type starting at column
+func parseDecimalArgs(fieldType, list string, column int) (precision, scale int, currency string, err error) {
+	if !strings.HasSuffix(list, ")") {
+		return 0, 0, "", &FieldError{
+			Column:     column,
+			Token:      list,
+			Message:    fmt.Sprintf("unterminated %s arguments", fieldType),
+			Suggestion: "add a closing )",
+		}
+	}
+	args := strings.TrimSpace(list[1 : len(list)-1])
+
+	if fieldType == "money" {
+		if !currencyPattern.MatchString(args) {
+			return 0, 0, "", &FieldError{
+				Column:     column + 1,
+				Token:      args,
+				Message:    fmt.Sprintf("invalid currency %q", args),
+				Suggestion: "use an ISO 4217 code, e.g. money(USD)",
+			}
+		}
+		currency = strings.ToUpper(args)
+		return moneyPrecision, currencyScale(currency), currency, nil
+	}
+
+	p, s, _ := strings.Cut(args, ",")
+	precision, perr := strconv.Atoi(strings.TrimSpace(p))
+	scale, serr := strconv.Atoi(strings.TrimSpace(s))
+	if perr != nil || serr != nil || precision < 1 || precision > 38 || scale < 0 || scale > precision {
+		return 0, 0, "", &FieldError{
+			Column:     column + 1,
+			Token:      args,
+			Message:    fmt.Sprintf("invalid precision and scale %q", args),
+			Suggestion: "e.g. decimal(10,4), with up to 38 digits",
+		}
+	}
+	return precision, scale, "", nil
+}
+
+// currencyScale returns the decimal places of a currency
+func currencyScale(currency string) int {
+	if scale, ok := currencyScales[currency]; ok {
+		return scale
+	}
+	return 2
+}
+
+// setDecimalType makes the field an exact decimal number edited as text
+func (f *TemplateField) setDecimalType(precision, scale int, currency string) {
+	f.IsDecimal = true
+	f.Precision = precision
+	f.Scale = scale
+	f.Currency = currency
+	f.TypeScriptType = "string"
+	f.ZeroValue = "''"
+	f.InitialValue = "''"
+}
+
+// decimalPattern returns the pattern of the field's values, without anchors
+func (f TemplateField) decimalPattern() string {
+	pattern := fmt.Sprintf(`-?\d{1,%d}`, max(f.Precision-f.Scale, 1))
+	if f.Scale > 0 {
+		pattern += fmt.Sprintf(`(\.\d{1,%d})?`, f.Scale)
+	}
+	return pattern
+}
+
+// DecimalPlaceholder returns zero with the field's decimal places, e.g. 0.00
+func (f TemplateField) DecimalPlaceholder() string {
+	return strings.TrimSuffix("0."+strings.Repeat("0", f.Scale), ".")
+}
+
+// decimalDescription describes the values of a field in error messages
+func decimalDescription(scale int) string {
+	switch scale {
+	case 0:
+		return "a whole number"
+	case 1:
+		return "a number with at most 1 decimal place"
+	}
+	return fmt.Sprintf("a number with at most %d decimal places", scale)
+}
+
+// checkDecimalDefault checks that a default value fits the field
+func checkDecimalDefault(f TemplateField, value string) error {
+	if !regexp.MustCompile(`^` + f.decimalPattern() + `$`).MatchString(value) {
+		return fmt.Errorf("default %q is not %s", value, decimalDescription(f.Scale))
+	}
+	return nil
+}
+
+// decimalZodSchema validates the text of a decimal input, which is empty
+// when the input is cleared
+func decimalZodSchema(f TemplateField) string {
+	description := decimalDescription(f.Scale)
+	if f.Required {
+		return fmt.Sprintf("z.string().min(1, '%s is required').regex(/^%s$/, '%s must be %s')", f.Label, f.decimalPattern(), f.Label, description)
+	}
+	return fmt.Sprintf("z.string().regex(/^(%s)?$/, '%s must be %s')", f.decimalPattern(), f.Label, description)
+}
+
+// DecimalMessage returns the validation message of a decimal field
+func (f BackendField) DecimalMessage() string {
+	return fmt.Sprintf("must be %s, up to %d digits in all", decimalDescription(f.Scale), f.Precision)
+}
+
+// decimalFile renders models/decimal.go, which the decimal fields of every
+// resource share, or returns nil when the fields have none
+func decimalFile(root string, data *BackendTemplateData) (*generatedFile, error) {
+	for _, f := range data.Fields {
+		if f.Type == "Decimal" {
+			t := projectTemplate(root, filepath.Join(root, "api", "models", "decimal.go"), "base/decimal.tmpl")
+			return renderFileTemplate(root, t, data)
+		}
+	}
+	return nil, nil
+}
+
+// decimalsFile renders utils/decimals.ts, which formats the decimal fields
+// of every resource, or returns nil when the fields have none
+func decimalsFile(root string, data *TemplateData) (*generatedFile, error) {
+	for _, f := range data.Fields {
+		if f.IsDecimal {
+			t := projectTemplate(root, filepath.Join(root, "vue", "app", "utils", "decimals.ts"), "frontend/decimals.ts")
+			return renderFileTemplate(root, t, data)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,169 @@
+package construct
+
+import "testing"
+
+func TestDecimalFields(t *testing.T) {
+	tests := []struct {
+		arg              string
+		precision, scale int
+		currency         string
+		zod, gorm        string
+		placeholder      string
+	}{
+		{
+			arg: "price:money", precision: 19, scale: 2, currency: "USD",
+			zod:         `z.string().regex(/^(-?\d{1,17}(\.\d{1,2})?)?$/, 'Price must be a number with at most 2 decimal places').optional()`,
+			gorm:        "type:decimal(19,2)",
+			placeholder: "0.00",
+		},
+		{
+			arg: "price:money(jpy):required", precision: 19, scale: 0, currency: "JPY",
+			zod:         `z.string().min(1, 'Price is required').regex(/^-?\d{1,19}$/, 'Price must be a whole number')`,
+			gorm:        "type:decimal(19,0);not null",
+			placeholder: "0",
+		},
+		{
+			arg: "fee:money(KWD)", precision: 19, scale: 3, currency: "KWD",
+			zod:         `z.string().regex(/^(-?\d{1,16}(\.\d{1,3})?)?$/, 'Fee must be a number with at most 3 decimal places').optional()`,
+			gorm:        "type:decimal(19,3)",
+			placeholder: "0.000",
+		},
+		{
+			arg: "rate:decimal", precision: 10, scale: 2,
+			zod:         `z.string().regex(/^(-?\d{1,8}(\.\d{1,2})?)?$/, 'Rate must be a number with at most 2 decimal places').optional()`,
+			gorm:        "type:decimal(10,2)",
+			placeholder: "0.00",
+		},
+		{
+			arg: "rate:decimal( 10 , 4 )", precision: 10, scale: 4,
+			zod:         `z.string().regex(/^(-?\d{1,6}(\.\d{1,4})?)?$/, 'Rate must be a number with at most 4 decimal places').optional()`,
+			gorm:        "type:decimal(10,4)",
+			placeholder: "0.0000",
+		},
+		{
+			arg: "total:decimal(5,0)=12", precision: 5, scale: 0,
+			zod:         `z.string().regex(/^(-?\d{1,5})?$/, 'Total must be a whole number').default('12')`,
+			gorm:        "type:decimal(5,0);not null;default:'12'",
+			placeholder: "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			f, b := backendField(t, tt.arg)
+			if !f.IsDecimal || f.Precision != tt.precision || f.Scale != tt.scale || f.Currency != tt.currency {
+				t.Errorf("decimal %v (%d,%d) in %q, want (%d,%d) in %q", f.IsDecimal, f.Precision, f.Scale, f.Currency, tt.precision, tt.scale, tt.currency)
+			}
+			if b.Type != "Decimal" || f.TypeScriptType != "string" {
+				t.Errorf("typed %s / %s, want Decimal / string", b.Type, f.TypeScriptType)
+			}
+			if f.ZodSchema != tt.zod {
+				t.Errorf("zod schema\n%s\nwant\n%s", f.ZodSchema, tt.zod)
+			}
+			if f.GORMTag != tt.gorm || f.DecimalPlaceholder() != tt.placeholder {
+				t.Errorf("gorm %q, placeholder %q, want %q, %q", f.GORMTag, f.DecimalPlaceholder(), tt.gorm, tt.placeholder)
+			}
+		})
+	}
+}
+
+func TestDecimalFieldErrors(t *testing.T) {
+	tests := []struct {
+		arg, message string
+	}{
+		{"price:money(US)", `invalid currency "US"`},
+		{"price:money(U5D)", `invalid currency "U5D"`},
+		{"rate:decimal(2,3)", `invalid precision and scale "2,3"`},
+		{"rate:decimal(39,2)", `invalid precision and scale "39,2"`},
+		{"rate:decimal(0,0)", `invalid precision and scale "0,0"`},
+		{"rate:decimal(10)", `invalid precision and scale "10"`},
+		{"rate:decimal(10,2", "unterminated decimal arguments"},
+		{"price:money=1.999", `default "1.999" is not a number with at most 2 decimal places`},
+		{"rate:decimal(4,2)=123", `default "123" is not a number with at most 2 decimal places`},
+		{"price:money(JPY)=1.5", `default "1.5" is not a whole number`},
+		{"rate:decimal:min=1", `modifier "min" does not apply to decimal fields`},
+	}
+	for _, tt := range tests {
+		_, err := parseField(tt.arg)
+		if fe, ok := err.(*FieldError); !ok || fe.Message != tt.message {
+			t.Errorf("parseField(%q) = %v, want %q", tt.arg, err, tt.message)
+		}
+	}
+}
+
+// decimalTest checks the generated Decimal against the columns it fills
+const decimalTest = `package models
+
+import "testing"
+
+func TestDecimalValid(t *testing.T) {
+	tests := []struct {
+		value            Decimal
+		precision, scale int
+		valid            bool
+	}{
+		{"12.50", 10, 2, true},
+		{"-0.5", 10, 2, true},
+		{"-12", 10, 2, true},
+		{"0", 10, 2, true},
+		{"00012.5", 10, 2, true}, // Leading zeros take no digits
+		{"99999999.99", 10, 2, true},
+		{"123456789.00", 10, 2, false}, // 9 digits before the point leave no room for 2 after it
+		{"1.234", 10, 2, false},
+		{"1234", 5, 0, true},
+		{"123456", 5, 0, false},
+		{"1.5", 5, 0, false},
+		{".5", 10, 2, false},
+		{"5.", 10, 2, false},
+		{"1e5", 10, 2, false},
+		{"+1", 10, 2, false},
+		{"--1", 10, 2, false},
+		{"1,000", 10, 2, false},
+		{" 1", 10, 2, false},
+		{"", 10, 2, false},
+	}
+	for _, tt := range tests {
+		if got := tt.value.Valid(tt.precision, tt.scale); got != tt.valid {
+			t.Errorf("Decimal(%q).Valid(%d, %d) = %v, want %v", tt.value, tt.precision, tt.scale, got, tt.valid)
+		}
+	}
+}
+
+func TestDecimalScan(t *testing.T) {
+	tests := []struct {
+		value any
+		want  Decimal
+	}{
+		{nil, ""},
+		{[]byte("12.50"), "12.50"},
+		{"0.10", "0.10"},
+		{int64(-7), "-7"},
+		{float64(2.5), "2.5"},
+	}
+	for _, tt := range tests {
+		var d Decimal = "x"
+		if err := d.Scan(tt.value); err != nil || d != tt.want {
+			t.Errorf("Scan(%#v) = %q, %v, want %q", tt.value, d, err, tt.want)
+		}
+	}
+	var d Decimal
+	if err := d.Scan(true); err == nil {
+		t.Error("Scan(true) succeeded")
+	}
+
+	if v, err := Decimal("").Value(); v != nil || err != nil {
+		t.Errorf("empty Decimal stores %v, %v, want NULL", v, err)
+	}
+	if v, err := Decimal("1.50").Value(); v != "1.50" || err != nil {
+		t.Errorf("Decimal(1.50) stores %v, %v, want the text", v, err)
+	}
+}
+`
+
+func TestGeneratedDecimal(t *testing.T) {
+	data, err := NewTemplateData("Product", []string{"price:money"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := builtinFile(t, "decimal.go", "base/decimal.tmpl", NewBackendTemplateData("example.com/app", data))
+	testGeneratedModels(t, file, &generatedFile{Path: "decimal_test.go", Content: []byte(decimalTest)})
+}
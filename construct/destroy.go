@@ -0,0 +1,239 @@
+package construct
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-go/mamba"
+)
+
+var destroyCmd = &mamba.Command{
+	Use:     "destroy [resource]",
+	Aliases: []string{"d", "d:b", "d:f", "destroy:b", "destroy:f"},
+	Short:   "Remove a generated resource",
+	Long: `Remove the files generated for a resource and unregister its module.
+
+Only the files construct g creates are removed. Files edited since they
+were generated are kept unless --force is given.
+
+Examples:
+  construct destroy Post
+  construct d:b Post          # Backend only
+  construct d:f Post          # Frontend only
+  construct d Post --dry-run  # List what would be removed
+
+Syntax:
+  d or destroy    Remove both backend and frontend
+  d:b             Remove backend only
+  d:f             Remove frontend only`,
+	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+		if len(args) != 1 {
+			cmd.Help()
+			os.Exit(1)
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		runDestroy(invokedName(cmd), args[0], force, dryRun)
+	},
+}
+
+func init() {
+	destroyCmd.Flags().Bool("force", false, "also remove files edited since they were generated")
+	destroyCmd.Flags().Bool("dry-run", false, "list the files that would be removed")
+}
+
+// destroyedFile is a generated file considered for removal
+type destroyedFile struct {
+	Path     string
+	Rel      string
+	Modified bool
+	Reason   string
+	stop     string // Parent directories are pruned up to here
+}
+
+func runDestroy(command, resourceName string, force, dryRun bool) {
+	printBanner()
+
+	root, err := findProjectRoot()
+	if err == nil {
+		err = loadProject(root)
+	}
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	backend, frontend := generateTargets(command)
+	switch {
+	case !frontend:
+		fmt.Printf("🗑️  Destroying %s backend...\n", resourceName)
+	case !backend:
+		fmt.Printf("🗑️  Destroying %s frontend...\n", resourceName)
+	default:
+		fmt.Printf("🗑️  Destroying %s...\n", resourceName)
+	}
+	fmt.Println()
+
+	removed, err := DestroyResource(root, resourceName, backend, frontend, force, dryRun)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	switch {
+	case removed == 0:
+		fmt.Printf("Nothing to remove for %s\n", resourceName)
+	case dryRun:
+		fmt.Println("🔍 Dry run, no files were removed")
+	default:
+		fmt.Printf("🎉 %s destroyed\n", resourceName)
+	}
+}
+
+// DestroyResource removes the files generated for a resource and its
+// registration in api/init.go. Unless forced, it refuses to remove anything
+// when a file was edited since it was generated. It returns how many files
+// and registrations were removed.
+func DestroyResource(root, resourceName string, backend, frontend, force, dryRun bool) (int, error) {
+	data, err := NewTemplateData(resourceName, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	manifest, err := loadManifest(root)
+	if err != nil {
+		return 0, err
+	}
+
+	var templates []fileTemplate
+	var modulePath string
+	if backend {
+		if modulePath, err = projectModulePath(root); err != nil {
+			return 0, err
+		}
+		templates = append(templates, backendTemplates(root, NewBackendTemplateData(modulePath, data))...)
+	}
+	if frontend {
+		templates = append(templates, frontendTemplates(root, data)...)
+	}
+
+	// Directories that belong to the resource and go away with it
+	resourceDirs := []string{
+		filepath.Join(root, "api", data.ModuleName),
+		filepath.Join(root, "vue", "app", data.DirName),
+	}
+
+	var files []destroyedFile
+	var modified []string
+	for _, t := range templates {
+		f := newGeneratedFile(root, t.path, nil)
+		current, err := os.ReadFile(t.path)
+		if os.IsNotExist(err) {
+			delete(manifest.Files, f.Rel) // Already removed by hand
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		// Shared directories such as api/models are kept
+		file := destroyedFile{Path: t.path, Rel: f.Rel, stop: filepath.Dir(t.path)}
+		for _, dir := range resourceDirs {
+			if strings.HasPrefix(t.path, dir+string(os.PathSeparator)) {
+				file.stop = filepath.Dir(dir)
+			}
+		}
+
+		if _, known := manifest.Files[f.Rel]; !known {
+			file.Modified, file.Reason = true, "not generated by construct"
+		} else if manifest.modified(f.Rel, current) {
+			file.Modified, file.Reason = true, "edited since it was generated"
+		}
+		if file.Modified {
+			modified = append(modified, fmt.Sprintf("     %s (%s)", f.Rel, file.Reason))
+		}
+		files = append(files, file)
+	}
+
+	if len(modified) > 0 && !force && !dryRun {
+		return 0, fmt.Errorf("%d file(s) would lose changes:\n%s\n   Re-run with --force to remove them anyway",
+			len(modified), strings.Join(modified, "\n"))
+	}
+
+	for _, f := range files {
+		if dryRun {
+			line := "  - remove    " + f.Rel
+			if f.Modified {
+				line = fmt.Sprintf("  ! remove    %s (%s)", f.Rel, f.Reason)
+			}
+			fmt.Println(line)
+			continue
+		}
+
+		if err := os.Remove(f.Path); err != nil {
+			return 0, fmt.Errorf("failed to remove %s: %w", f.Rel, err)
+		}
+		removeEmptyDirs(filepath.Dir(f.Path), f.stop)
+		if err := manifest.forget(root, f.Rel); err != nil {
+			return 0, err
+		}
+		fmt.Printf("  ✓ Removed %s\n", f.Rel)
+	}
+
+	removed := len(files)
+	initPath := filepath.Join(root, "api", "init.go")
+	if backend && fileExists(initPath) {
+		if dryRun {
+			reg, err := loadModuleRegistry(initPath)
+			if err != nil {
+				return 0, err
+			}
+			if reg.findRegistration(data.ModuleName) != nil {
+				fmt.Println("  ~ modify    api/init.go")
+				removed++
+			}
+		} else {
+			unregistered, err := UnregisterModule(initPath, modulePath, data.ModuleName)
+			if err != nil {
+				return 0, fmt.Errorf("failed to unregister module from api/init.go: %w", err)
+			}
+			if unregistered {
+				fmt.Printf("  ✓ Unregistered %s module from api/init.go\n", data.ModuleName)
+				removed++
+			}
+		}
+	}
+
+	if dryRun || removed == 0 {
+		return removed, nil
+	}
+
+	if err := manifest.save(root); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", manifestPath, err)
+	}
+
+	// Let construct g --from generate the resource again
+	state, err := loadSchemaState(root)
+	if err != nil {
+		return 0, err
+	}
+	changed := false
+	for side, destroyed := range map[string]bool{"backend": backend, "frontend": frontend} {
+		if _, ok := state[data.ResourceName+"/"+side]; ok && destroyed {
+			delete(state, data.ResourceName+"/"+side)
+			changed = true
+		}
+	}
+	if changed {
+		if err := saveSchemaState(root, state); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %w", schemaStatePath, err)
+		}
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,60 @@
+package construct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDestroyResource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // No user template overrides
+	tests := []struct {
+		name              string
+		edit              bool // Edit the service after generating it
+		backend, frontend bool
+		force, dryRun     bool
+		fails             bool
+		apiGone, vueGone  bool
+	}{
+		{name: "full stack", backend: true, frontend: true, apiGone: true, vueGone: true},
+		{name: "backend only", backend: true, apiGone: true},
+		{name: "frontend only", frontend: true, vueGone: true},
+		{name: "dry run", backend: true, frontend: true, dryRun: true},
+		{name: "edited", edit: true, backend: true, frontend: true, fails: true},
+		{name: "edited, dry run", edit: true, backend: true, frontend: true, dryRun: true},
+		{name: "edited, forced", edit: true, backend: true, frontend: true, force: true, apiGone: true, vueGone: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newFieldProject(t, "title:string")
+			if tt.edit {
+				editProject(t, root)
+			}
+
+			removed, err := DestroyResource(root, "Post", tt.backend, tt.frontend, tt.force, tt.dryRun)
+			if (err != nil) != tt.fails {
+				t.Fatalf("DestroyResource() = %v, want failure %v", err, tt.fails)
+			}
+			if !tt.fails && removed == 0 {
+				t.Errorf("DestroyResource() removed nothing")
+			}
+
+			for dir, gone := range map[string]bool{"api/posts": tt.apiGone, "vue/app/posts": tt.vueGone} {
+				if fileExists(filepath.Join(root, dir)) == gone {
+					t.Errorf("%s exists: %v, want %v", dir, gone, !gone)
+				}
+			}
+			init, err := os.ReadFile(filepath.Join(root, "api", "init.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if registered := strings.Contains(string(init), `modules["posts"]`); registered == tt.apiGone {
+				t.Errorf("posts registered in api/init.go: %v, want %v", registered, !tt.apiGone)
+			}
+			if !fileExists(filepath.Join(root, "api", "models")) {
+				t.Errorf("api/models was removed along with the resource")
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ var devCmd = &mamba.Command{
 	Short: "Start development servers",
 	Long:  "Start both Go API (port 8100) and Vue dev server (port 3100) with hot reload",
 	Run: func(cmd *mamba.Command, args []string) {
+		parseFlags(cmd, args)
 		verbose, _ := cmd.Flags().GetBool("verbose")
 		runDev(verbose)
 	},
@@ -0,0 +1,244 @@
+package construct
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffOp is one line of an edit script turning a into b
+type diffOp struct {
+	kind byte // ' ' kept, '-' removed from a, '+' added from b
+	line string
+}
+
+// splitLines splits content into lines without their line endings
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// diffLines computes a shortest edit script between two sets of lines using
+// their longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	// Common prefix and suffix need no table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int, len(am)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bm)+1)
+	}
+	for i := len(am) - 1; i >= 0; i-- {
+		for j := len(bm) - 1; j >= 0; j-- {
+			if am[i] == bm[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(am) || j < len(bm) {
+		switch {
+		case i < len(am) && j < len(bm) && am[i] == bm[j]:
+			ops = append(ops, diffOp{' ', am[i]})
+			i++
+			j++
+		case i < len(am) && (j == len(bm) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', am[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', bm[j]})
+			j++
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// unifiedDiff renders the difference between two versions of a file in
+// unified diff format. It returns an empty string if they are equal.
+func unifiedDiff(oldName, newName, oldContent, newContent string) string {
+	ops := diffLines(splitLines(oldContent), splitLines(newContent))
+
+	// Line numbers in a and b at the start of each op
+	aLine, bLine := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for k, op := range ops {
+		aLine[k+1], bLine[k+1] = aLine[k], bLine[k]
+		if op.kind != '+' {
+			aLine[k+1]++
+		}
+		if op.kind != '-' {
+			bLine[k+1]++
+		}
+	}
+
+	var out strings.Builder
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			k++
+			continue
+		}
+
+		// Extend the hunk while the next change is within reach of its context
+		start := max(k-diffContext, 0)
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContext {
+				end = min(end+diffContext, len(ops))
+				break
+			}
+			end = next
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(aLine[start], aLine[end]-aLine[start]),
+			hunkRange(bLine[start], bLine[end]-bLine[start]))
+		for _, op := range ops[start:end] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			out.WriteByte('\n')
+		}
+		k = end
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the start,count part of a hunk header
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// Conflict markers written by merge3
+const (
+	conflictStart = "<<<<<<< local"
+	conflictSep   = "======="
+	conflictEnd   = ">>>>>>> generated"
+)
+
+// merge3 merges the changes made to base in local and in generated, the
+// way diff3 does. Where both sides changed the same lines differently it
+// keeps both between conflict markers, and it returns how many such
+// conflicts there were.
+func merge3(base, local, generated string) (string, int) {
+	o, a, b := splitLines(base), splitLines(local), splitLines(generated)
+	ma, mb := lineMatches(o, a), lineMatches(o, b)
+
+	var out []string
+	conflicts := 0
+	i, ia, ib := 0, 0, 0
+	for {
+		// Copy lines neither side touched
+		for i < len(o) && ma[i] == ia && mb[i] == ib {
+			out = append(out, o[i])
+			i, ia, ib = i+1, ia+1, ib+1
+		}
+		if i == len(o) && ia == len(a) && ib == len(b) {
+			break
+		}
+
+		// The changed chunk ends at the next base line both sides kept
+		next := i
+		for next < len(o) && (ma[next] < 0 || mb[next] < 0) {
+			next++
+		}
+		ea, eb := len(a), len(b)
+		if next < len(o) {
+			ea, eb = ma[next], mb[next]
+		}
+
+		chunkO, chunkA, chunkB := o[i:next], a[ia:ea], b[ib:eb]
+		switch {
+		case equalLines(chunkA, chunkO):
+			out = append(out, chunkB...)
+		case equalLines(chunkB, chunkO), equalLines(chunkA, chunkB):
+			out = append(out, chunkA...)
+		default:
+			out = append(out, conflictStart)
+			out = append(out, chunkA...)
+			out = append(out, conflictSep)
+			out = append(out, chunkB...)
+			out = append(out, conflictEnd)
+			conflicts++
+		}
+		i, ia, ib = next, ea, eb
+	}
+
+	if len(out) == 0 {
+		return "", conflicts
+	}
+	return strings.Join(out, "\n") + "\n", conflicts
+}
+
+// lineMatches maps each line of base to the line of other it was kept as,
+// or -1 if it was removed
+func lineMatches(base, other []string) []int {
+	matches := make([]int, len(base))
+	i, j := 0, 0
+	for _, op := range diffLines(base, other) {
+		switch op.kind {
+		case ' ':
+			matches[i] = j
+			i, j = i+1, j+1
+		case '-':
+			matches[i] = -1
+			i++
+		case '+':
+			j++
+		}
+	}
+	return matches
+}
+
+// equalLines reports whether two sets of lines are identical
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
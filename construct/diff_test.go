@@ -0,0 +1,142 @@
+package construct
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string // One op per line, kind then text
+	}{
+		{"equal", "a\nb\n", "a\nb\n", " a\n b\n"},
+		{"empty to lines", "", "a\nb\n", "+a\n+b\n"},
+		{"lines to empty", "a\nb\n", "", "-a\n-b\n"},
+		{"insert in the middle", "a\nc\n", "a\nb\nc\n", " a\n+b\n c\n"},
+		{"insert at the end", "a\nb\n", "a\nb\nc\n", " a\n b\n+c\n"},
+		{"remove", "a\nb\nc\n", "a\nc\n", " a\n-b\n c\n"},
+		{"replace", "a\nb\nc\n", "a\nx\nc\n", " a\n-b\n+x\n c\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got strings.Builder
+			for _, op := range diffLines(splitLines(tt.a), splitLines(tt.b)) {
+				got.WriteByte(op.kind)
+				got.WriteString(op.line + "\n")
+			}
+			if got.String() != tt.want {
+				t.Errorf("diffLines(%q, %q) =\n%s\nwant\n%s", tt.a, tt.b, got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesReproducesBothSides(t *testing.T) {
+	a := splitLines("package x\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n")
+	b := splitLines("package x\n\nfunc B() {}\n\nfunc D() {}\n\nfunc C() {}\n\nfunc E() {}\n")
+
+	var gotA, gotB []string
+	for _, op := range diffLines(a, b) {
+		if op.kind != '+' {
+			gotA = append(gotA, op.line)
+		}
+		if op.kind != '-' {
+			gotB = append(gotB, op.line)
+		}
+	}
+	if !equalLines(gotA, a) || !equalLines(gotB, b) {
+		t.Errorf("edit script does not turn a into b:\n%q\n%q", gotA, gotB)
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name                   string
+		base, local, generated string
+		want                   string
+		conflicts              int
+	}{
+		{
+			name:      "no changes",
+			base:      "a\nb\nc\n",
+			local:     "a\nb\nc\n",
+			generated: "a\nb\nc\n",
+			want:      "a\nb\nc\n",
+		},
+		{
+			name:      "only generated changed",
+			base:      "a\nb\nc\n",
+			local:     "a\nb\nc\n",
+			generated: "a\nB\nc\n",
+			want:      "a\nB\nc\n",
+		},
+		{
+			name:      "only local changed",
+			base:      "a\nb\nc\n",
+			local:     "a\nb\nlocal\nc\n",
+			generated: "a\nb\nc\n",
+			want:      "a\nb\nlocal\nc\n",
+		},
+		{
+			name:      "clean merge of separate changes",
+			base:      "a\nb\nc\nd\ne\n",
+			local:     "a\nlocal\nc\nd\ne\n",
+			generated: "a\nb\nc\nd\ngenerated\n",
+			want:      "a\nlocal\nc\nd\ngenerated\n",
+		},
+		{
+			name:      "both sides made the same edit",
+			base:      "a\nb\nc\n",
+			local:     "a\nx\nc\n",
+			generated: "a\nx\nc\n",
+			want:      "a\nx\nc\n",
+		},
+		{
+			name:      "insert at the end of the file",
+			base:      "a\nb\n",
+			local:     "local\na\nb\n",
+			generated: "a\nb\ngenerated\n",
+			want:      "local\na\nb\ngenerated\n",
+		},
+		{
+			name:      "both insert at the end of the file",
+			base:      "a\nb\n",
+			local:     "a\nb\nlocal\n",
+			generated: "a\nb\ngenerated\n",
+			want:      "a\nb\n" + conflictStart + "\nlocal\n" + conflictSep + "\ngenerated\n" + conflictEnd + "\n",
+			conflicts: 1,
+		},
+		{
+			name:      "conflict",
+			base:      "a\nb\nc\n",
+			local:     "a\nlocal\nc\n",
+			generated: "a\ngenerated\nc\n",
+			want:      "a\n" + conflictStart + "\nlocal\n" + conflictSep + "\ngenerated\n" + conflictEnd + "\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "local removed what generated kept",
+			base:      "a\nb\nc\n",
+			local:     "a\nc\n",
+			generated: "a\nb\nc\nd\n",
+			want:      "a\nc\nd\n",
+		},
+		{
+			name:      "everything removed",
+			base:      "a\n",
+			local:     "",
+			generated: "a\n",
+			want:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := merge3(tt.base, tt.local, tt.generated)
+			if got != tt.want || conflicts != tt.conflicts {
+				t.Errorf("merge3() = %q with %d conflict(s), want %q with %d", got, conflicts, tt.want, tt.conflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package construct
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// The display field names a record in delete and edit modals, in the select
+// options other resources pick it with and in the responses that embed it.
+// It is the first of these fields the resource has:
+//
+//	title, name, label, email     by name
+//	the first string field        otherwise
+//
+// and can be chosen with --display, or display: in a schema file. A resource
+// with none of them is shown by its id, e.g. "Product #3".
+
+// displayFieldNames are the fields preferred as display field, in order
+var displayFieldNames = []string{"title", "name", "label", "email"}
+
+// displayTypes are the field types a record can be displayed by
+var displayTypes = []string{"string", "text", "email", "url", "slug"}
+
+// chooseDisplayField picks the display field of a resource, or returns ""
+// when no field can name its records
+func chooseDisplayField(fields []TemplateField) string {
+	for _, name := range displayFieldNames {
+		for _, f := range fields {
+			if f.Name == name && isDisplayable(f) {
+				return f.Name
+			}
+		}
+	}
+	for _, f := range fields {
+		if f.Type == "string" && isDisplayable(f) {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// isDisplayable reports whether a record can be displayed by the field
+func isDisplayable(f TemplateField) bool {
+	return f.Relationship == "" && !f.IsNullable && containsString(displayTypes, f.Type)
+}
+
+// displayedTemplateData creates template data displayed by the named field,
+// or by the one chooseDisplayField picks when display is empty
+func displayedTemplateData(resourceName string, fieldArgs []string, display string) (*TemplateData, error) {
+	if display == "" {
+		return NewTemplateData(resourceName, fieldArgs)
+	}
+	data, err := NewTemplateDataWithDisplay(resourceName, fieldArgs, display)
+	if err != nil {
+		return nil, err
+	}
+	return data, data.checkDisplayField()
+}
+
+// checkDisplayField checks that the display field is one of the fields and
+// can name a record
+func (d *TemplateData) checkDisplayField() error {
+	var names []string
+	for _, f := range d.Fields {
+		if !isDisplayable(f) {
+			continue
+		}
+		if f.Name == d.DisplayField {
+			return nil
+		}
+		names = append(names, f.Name)
+	}
+	if d.hasField(d.DisplayField) {
+		return fmt.Errorf("%s cannot be the display field, only string, text, email, url and slug fields that are not nullable can", d.DisplayField)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%s has no %s field to display, and no field that can be displayed", d.ResourceName, d.DisplayField)
+	}
+	return fmt.Errorf("%s has no %s field to display (%s)", d.ResourceName, d.DisplayField, suggestion(d.DisplayField, names))
+}
+
+// DisplayValue returns the TypeScript expression naming the record held in
+// a variable, e.g. post.title, or 'Post #' + post.id without display field
+func (d *TemplateData) DisplayValue(record string) string {
+	if d.DisplayField == "" {
+		return "'" + d.ResourceName + " #' + " + record + ".id"
+	}
+	return record + "." + d.DisplayField
+}
+
+// displayedByPattern matches the comment types.ts puts above the interface
+// of a record displayed by one of its fields
+var displayedByPattern = regexp.MustCompile(`(?m)^// Displayed by (\w+)\n(?://.*\n)*export interface (\w+) \{`)
@@ -0,0 +1,84 @@
+package construct
+
+import "testing"
+
+func TestChooseDisplayField(t *testing.T) {
+	tests := []struct {
+		fields  []string
+		display string
+	}{
+		{[]string{"body:text", "title:string"}, "title"},
+		{[]string{"email:email", "name:string"}, "name"},
+		{[]string{"code:string", "label:string"}, "label"},
+		{[]string{"views:int", "email:email"}, "email"},
+		{[]string{"views:int", "sku:string", "code:string"}, "sku"},
+		{[]string{"title:string?", "code:string"}, "code"},
+		{[]string{"name:belongs_to", "code:string"}, "code"},
+		{[]string{"title:text"}, "title"},
+		{[]string{"body:text", "website:url"}, ""},
+		{[]string{"views:int", "active:bool"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		data, err := NewTemplateData("Product", tt.fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.DisplayField != tt.display {
+			t.Errorf("%q is displayed by %q, want %q", tt.fields, data.DisplayField, tt.display)
+		}
+	}
+}
+
+func TestDisplayFlag(t *testing.T) {
+	fields := []string{"title:string", "code:string", "summary:text?", "views:int"}
+	tests := []struct {
+		display, err string // err is "" when valid
+	}{
+		{"", ""},
+		{"code", ""},
+		{"title", ""},
+		{"views", "views cannot be the display field, only string, text, email, url and slug fields that are not nullable can"},
+		{"summary", "summary cannot be the display field, only string, text, email, url and slug fields that are not nullable can"},
+		{"cod", `Product has no cod field to display (did you mean "code"?)`},
+		{"price", "Product has no price field to display (expected one of: title, code)"},
+	}
+	for _, tt := range tests {
+		data, err := displayedTemplateData("Product", fields, tt.display)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("--display %s = %v, want %q", tt.display, err, tt.err)
+			}
+			continue
+		}
+		want := tt.display
+		if want == "" {
+			want = "title"
+		}
+		if err != nil || data.DisplayField != want {
+			t.Errorf("--display %q = %v, displayed by %q, want %q", tt.display, err, data.DisplayField, want)
+		}
+	}
+
+	if _, err := displayedTemplateData("Product", []string{"views:int"}, "title"); err == nil || err.Error() != "Product has no title field to display, and no field that can be displayed" {
+		t.Errorf("--display title without displayable fields = %v", err)
+	}
+}
+
+func TestDisplayValue(t *testing.T) {
+	for _, tt := range []struct {
+		fields []string
+		value  string
+	}{
+		{[]string{"title:string"}, "product.title"},
+		{[]string{"views:int"}, "'Product #' + product.id"},
+	} {
+		data, err := NewTemplateData("Product", tt.fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := data.DisplayValue("product"); got != tt.value {
+			t.Errorf("%q displays %s, want %s", tt.fields, got, tt.value)
+		}
+	}
+}
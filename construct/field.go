@@ -0,0 +1,735 @@
+package construct
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/base-go/mamba"
+)
+
+var fieldAddCmd = &mamba.Command{
+	Use:     "g:field [resource] [fields...]",
+	Aliases: []string{"gen:field", "generate:field"},
+	Short:   "Add fields to a generated resource",
+	Long: `Add fields to a resource that was already generated.
+
+The Go model, request structs and service are edited through their syntax
+tree, and the Vue types, form and table between their construct:fields
+markers, so hand edits elsewhere in the files are kept.
+
+Examples:
+  construct g:field Post views:int
+  construct g:field Post subtitle:string:max=120 featured:bool
+  construct g:field Post views:int --diff   # Preview the changes`,
+	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+		if len(args) < 2 {
+			cmd.Help()
+			os.Exit(1)
+		}
+		runFieldCommand(cmd, func(root string, opts GenerateOptions) error {
+			return AddFields(root, args[0], args[1:], opts)
+		})
+	},
+}
+
+var fieldRemoveCmd = &mamba.Command{
+	Use:     "rm:field [resource] [fields...]",
+	Aliases: []string{"remove:field"},
+	Short:   "Remove fields from a generated resource",
+	Long: `Remove fields from a resource that was already generated.
+
+Examples:
+  construct rm:field Post views
+  construct rm:field Post views featured --dry-run`,
+	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+		if len(args) < 2 {
+			cmd.Help()
+			os.Exit(1)
+		}
+		runFieldCommand(cmd, func(root string, opts GenerateOptions) error {
+			return RemoveFields(root, args[0], args[1:], opts)
+		})
+	},
+}
+
+var fieldRenameCmd = &mamba.Command{
+	Use:     "mv:field [resource] [old] [new]",
+	Aliases: []string{"rename:field"},
+	Short:   "Rename a field of a generated resource",
+	Long: `Rename a field of a resource that was already generated.
+
+The JSON name and database column change with the field. Existing data is
+not moved to the new column.
+
+Examples:
+  construct mv:field Post body content`,
+	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+		if len(args) != 3 {
+			cmd.Help()
+			os.Exit(1)
+		}
+		runFieldCommand(cmd, func(root string, opts GenerateOptions) error {
+			return RenameField(root, args[0], args[1], args[2], opts)
+		})
+	},
+}
+
+func init() {
+	for _, cmd := range []*mamba.Command{fieldAddCmd, fieldRemoveCmd, fieldRenameCmd} {
+		cmd.Flags().Bool("dry-run", false, "list the files that would be modified")
+		cmd.Flags().Bool("diff", false, "show a unified diff of every change (implies --dry-run)")
+	}
+}
+
+func runFieldCommand(cmd *mamba.Command, run func(root string, opts GenerateOptions) error) {
+	printBanner()
+
+	root, err := findProjectRoot()
+	if err == nil {
+		err = loadProject(root)
+	}
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var opts GenerateOptions
+	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.Diff, _ = cmd.Flags().GetBool("diff")
+
+	if err := run(root, opts); err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	if opts.preview() {
+		fmt.Println("🔍 Dry run, no files were written")
+	} else {
+		fmt.Println("🎉 Fields updated")
+	}
+}
+
+// generatedTemplateData creates the template data of fields of a generated
+// resource, which keeps the display field it was generated with
+func generatedTemplateData(resourceName string, fieldArgs []string) (*TemplateData, error) {
+	return NewTemplateDataWithDisplay(resourceName, fieldArgs, generatedResources[toPascalCase(resourceName)].Display)
+}
+
+// AddFields adds fields to the generated files of a resource
+func AddFields(root, resourceName string, fieldArgs []string, opts GenerateOptions) error {
+	data, err := generatedTemplateData(resourceName, nil)
+	if err != nil {
+		return err
+	}
+	withData, err := generatedTemplateData(resourceName, fieldArgs)
+	if err != nil {
+		return err
+	}
+
+	existing, err := generatedFields(root, data)
+	if err != nil {
+		return err
+	}
+	for _, f := range withData.Fields {
+		if _, ok := existing[f.Name]; ok {
+			return fmt.Errorf("%s already has a %s field", resourceName, f.Name)
+		}
+	}
+	if err := withData.checkSlugSources(existing); err != nil {
+		return err
+	}
+
+	files, err := patchGoFiles(root, data, withData, func(p *goFieldPatch, target *goSource) ([]sourceEdit, []string) {
+		return p.add(target)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The models of nullable and decimal fields are shared by every resource
+	if len(files) > 0 {
+		modulePath, err := projectModulePath(root)
+		if err != nil {
+			return err
+		}
+		withBackendData := NewBackendTemplateData(modulePath, withData)
+		for _, shared := range sharedBackendFiles {
+			file, err := shared(root, withBackendData)
+			if err != nil {
+				return err
+			}
+			if file != nil && !fileExists(file.Path) {
+				files = append(files, file)
+			}
+		}
+	}
+
+	frontend, err := patchFrontendFiles(root, data, func(current string, render renderFunc) (string, []string, error) {
+		rendered, err := render(fieldArgs)
+		if err != nil {
+			return "", nil, err
+		}
+		content, missing := addRegionFields(current, rendered)
+		var warnings []string
+		for _, region := range missing {
+			warnings = append(warnings, fmt.Sprintf("no construct:fields %s marker, add the new fields there by hand", region))
+		}
+		return content, warnings, nil
+	})
+	if err != nil {
+		return err
+	}
+	files = append(files, frontend...)
+
+	// The helpers of date, json and decimal fields are shared by every resource
+	if len(frontend) > 0 {
+		for _, shared := range sharedFrontendFiles {
+			file, err := shared(root, withData)
+			if err != nil {
+				return err
+			}
+			if file != nil && !fileExists(file.Path) {
+				files = append(files, file)
+			}
+		}
+	}
+
+	return writeFieldFiles(root, files, opts)
+}
+
+// RemoveFields removes fields from the generated files of a resource
+func RemoveFields(root, resourceName string, names []string, opts GenerateOptions) error {
+	data, err := generatedTemplateData(resourceName, nil)
+	if err != nil {
+		return err
+	}
+
+	existing, err := generatedFields(root, data)
+	if err != nil {
+		return err
+	}
+	var fieldArgs []string
+	for _, name := range names {
+		fieldType, ok := existing[name]
+		if !ok {
+			return fmt.Errorf("%s has no %s field", resourceName, name)
+		}
+		if name == data.DisplayField {
+			return fmt.Errorf("%s is the display field of %s and is used by its select options, remove it by hand", name, resourceName)
+		}
+		if slug, ok := derivedSlug(existing, name); ok && !containsString(names, slug) {
+			return fmt.Errorf("%s is the source of the slug %s, remove %s too", name, slug, slug)
+		}
+		fieldArgs = append(fieldArgs, name+":"+fieldType)
+	}
+	withData, err := generatedTemplateData(resourceName, fieldArgs)
+	if err != nil {
+		return err
+	}
+	var goNames []string
+	for _, f := range withData.Fields {
+		goNames = append(goNames, f.FieldName)
+		goNames = append(goNames, f.relationGoNames()...)
+		goNames = append(goNames, f.attachmentGoNames()...)
+		goNames = append(goNames, f.filterGoNames()...)
+		goNames = append(goNames, f.slugGoNames()...)
+	}
+
+	files, err := patchGoFiles(root, data, withData, func(p *goFieldPatch, target *goSource) ([]sourceEdit, []string) {
+		return p.remove(target, goNames), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	frontend, err := patchFrontendFiles(root, data, func(current string, render renderFunc) (string, []string, error) {
+		for i, arg := range fieldArgs {
+			rendered, err := render([]string{arg})
+			if err != nil {
+				return "", nil, err
+			}
+			current, _ = removeRegionField(current, rendered, names[i])
+		}
+		return current, nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeFieldFiles(root, append(files, frontend...), opts)
+}
+
+// RenameField renames a field in the generated files of a resource
+func RenameField(root, resourceName, oldName, newName string, opts GenerateOptions) error {
+	if !fieldNamePattern.MatchString(newName) {
+		return fmt.Errorf("invalid field name %q: use letters, digits and underscores, starting with a letter", newName)
+	}
+	if err := checkReservedFieldName(newName); err != nil {
+		return err
+	}
+
+	data, err := generatedTemplateData(resourceName, nil)
+	if err != nil {
+		return err
+	}
+
+	existing, err := generatedFields(root, data)
+	if err != nil {
+		return err
+	}
+	fieldType, ok := existing[oldName]
+	if !ok {
+		return fmt.Errorf("%s has no %s field", resourceName, oldName)
+	}
+	if _, ok := existing[newName]; ok {
+		return fmt.Errorf("%s already has a %s field", resourceName, newName)
+	}
+	if oldName == data.DisplayField {
+		return fmt.Errorf("%s is the display field of %s and is used by its select options, rename it by hand", oldName, resourceName)
+	}
+	if slug, ok := derivedSlug(existing, oldName); ok {
+		return fmt.Errorf("%s is the source of the slug %s, rename it by hand", oldName, slug)
+	}
+
+	withData, err := generatedTemplateData(resourceName, []string{oldName + ":" + fieldType})
+	if err != nil {
+		return err
+	}
+	oldField := withData.Fields[0]
+	if oldField.Relationship != "" {
+		return fmt.Errorf("%s is a %s relation to %s, remove it and add the new field instead", oldName, oldField.Relationship, oldField.RelatedModel)
+	}
+	if oldField.IsAttachment {
+		return fmt.Errorf("%s is an attachment stored under its field name, remove it and add the new field instead", oldName)
+	}
+	if oldField.IsSlug {
+		return fmt.Errorf("%s is a slug with its own route, remove it and add the new field instead", oldName)
+	}
+	newField := newTemplateField(newName, fieldType)
+
+	files, err := patchGoFiles(root, data, withData, func(p *goFieldPatch, target *goSource) ([]sourceEdit, []string) {
+		return p.rename(target, oldField, newField), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	frontend, err := patchFrontendFiles(root, data, func(current string, render renderFunc) (string, []string, error) {
+		rendered, err := render([]string{oldName + ":" + fieldType})
+		if err != nil {
+			return "", nil, err
+		}
+		content, _ := renameRegionField(current, rendered, oldField.Name, newField.Name, oldField.Label, newField.Label)
+		return content, nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writeFieldFiles(root, append(files, frontend...), opts); err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		fmt.Printf("  ⚠️  The %s column is now %s; existing data is not moved\n", oldField.Name, newField.Name)
+	}
+	return nil
+}
+
+// patchGoFiles applies an edit to each generated Go file of a resource that
+// exists, given the templates rendered without and with the fields
+func patchGoFiles(root string, data, withData *TemplateData, edit func(p *goFieldPatch, target *goSource) ([]sourceEdit, []string)) ([]*generatedFile, error) {
+	if !fileExists(filepath.Join(root, "go.mod")) {
+		return nil, nil
+	}
+	modulePath, err := projectModulePath(root)
+	if err != nil {
+		return nil, err
+	}
+	backendData := NewBackendTemplateData(modulePath, data)
+	withBackendData := NewBackendTemplateData(modulePath, withData)
+
+	var files []*generatedFile
+	for _, t := range backendTemplates(root, backendData) {
+		if !fileExists(t.path) {
+			continue
+		}
+		without, err := renderFileTemplate(root, t, backendData)
+		if err != nil {
+			return nil, err
+		}
+		with, err := renderFileTemplate(root, t, withBackendData)
+		if err != nil {
+			return nil, err
+		}
+		patch, err := newGoFieldPatch(t.path, without.Content, with.Content)
+		if err != nil {
+			return nil, err
+		}
+		target, err := loadGoSource(t.path)
+		if err != nil {
+			return nil, err
+		}
+
+		edits, warnings := edit(patch, target)
+		for _, w := range warnings {
+			fmt.Printf("  ⚠️  %s: %s\n", with.Rel, w)
+		}
+		if len(edits) == 0 {
+			continue
+		}
+		content, err := target.render(edits)
+		if err == nil {
+			content, err = formatGoSource(content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update %s: %w", with.Rel, err)
+		}
+		files = append(files, patchedFile(root, t, content))
+	}
+	return files, nil
+}
+
+// renderFunc renders a template with the given field definitions
+type renderFunc func(fieldArgs []string) (string, error)
+
+// patchFrontendFiles applies an edit to each generated frontend file of a
+// resource that exists and has construct:fields markers in its template
+func patchFrontendFiles(root string, data *TemplateData, edit func(current string, render renderFunc) (string, []string, error)) ([]*generatedFile, error) {
+	var files []*generatedFile
+	for _, t := range frontendTemplates(root, data) {
+		if !strings.Contains(t.template, "construct:fields") {
+			continue
+		}
+		current, err := os.ReadFile(t.path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		render := func(fieldArgs []string) (string, error) {
+			fieldData, err := NewTemplateDataWithDisplay(data.ResourceName, fieldArgs, data.DisplayField)
+			if err != nil {
+				return "", err
+			}
+			f, err := renderFileTemplate(root, t, fieldData)
+			if err != nil {
+				return "", err
+			}
+			return string(f.Content), nil
+		}
+		content, warnings, err := edit(string(current), render)
+		if err != nil {
+			return nil, err
+		}
+
+		f := patchedFile(root, t, []byte(content))
+		for _, w := range warnings {
+			fmt.Printf("  ⚠️  %s: %s\n", f.Rel, w)
+		}
+		if content != string(current) {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// patchedFile creates the generated file for the patched content of a file
+// rendered from a template
+func patchedFile(root string, t fileTemplate, content []byte) *generatedFile {
+	f := newGeneratedFile(root, t.path, content)
+	f.Template, f.TemplateVersion = t.name, templateVersion(t.template)
+	return f
+}
+
+// writeFieldFiles writes patched files. Files that still match their
+// generated output are recorded in the manifest with the new content; files
+// edited by hand are written as patches and keep their recorded base. Files
+// that do not exist yet are created.
+func writeFieldFiles(root string, files []*generatedFile, opts GenerateOptions) error {
+	if len(files) == 0 {
+		fmt.Println("  • Nothing to change")
+		return nil
+	}
+
+	manifest, err := loadManifest(root)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		current, err := os.ReadFile(f.Path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		f.Patch = manifest.modified(f.Rel, current)
+	}
+	return writeFiles(root, files, opts)
+}
+
+// generatedFields returns the fields of a generated resource and their field
+// types, read from its Go model or, for frontend-only resources, its
+// TypeScript types
+func generatedFields(root string, data *TemplateData) (map[string]string, error) {
+	modelPath := filepath.Join(root, "api", "models", toSnakeCase(data.ResourceName)+".go")
+	if fileExists(modelPath) {
+		model, err := loadGoSource(modelPath)
+		if err != nil {
+			return nil, err
+		}
+		if st, ok := structTypes(model.file)[data.ResourceName]; ok {
+			return modelFields(model.file, st), nil
+		}
+	}
+
+	typesPath := filepath.Join(root, "vue", "app", data.DirName, "types", data.LowerResourceName+".ts")
+	content, err := os.ReadFile(typesPath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s has not been generated yet, run construct g %s first", data.ResourceName, data.ResourceName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return interfaceFields(string(content), data.ResourceName), nil
+}
+
+// modelFields reads the fields of a Go model struct, skipping the columns
+// every model has. Relations are read from their belongs_to objects and
+// many2many lists, attachments from their storage.Attachment fields.
+func modelFields(file *ast.File, st *ast.StructType) map[string]string {
+	fields := map[string]string{}
+	foreignKeys := map[string]string{}
+	for _, field := range st.Fields.List {
+		if len(field.Names) != 1 || field.Tag == nil || isScalarType(field.Type) {
+			continue
+		}
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		gorm := tag.Get("gorm")
+		switch t := field.Type.(type) {
+		case *ast.StarExpr:
+			if exprName(t.X) == "storage.Attachment" {
+				// Image and file fields share their model field and read
+				// back as files
+				name, _, _ := strings.Cut(tag.Get("json"), ",")
+				fields[name] = "file"
+				continue
+			}
+			if _, fk, ok := strings.Cut(gorm, "foreignKey:"); ok {
+				fk, _, _ = strings.Cut(fk, ";")
+				foreignKeys[fk] = exprName(t.X)
+			}
+		case *ast.ArrayType:
+			if star, ok := t.Elt.(*ast.StarExpr); ok && strings.Contains(gorm, "many2many:") {
+				name, _, _ := strings.Cut(tag.Get("json"), ",")
+				fields[singularize(name)+"_ids"] = "many_to_many(" + exprName(star.X) + ")"
+			}
+		}
+	}
+
+	for _, field := range st.Fields.List {
+		if len(field.Names) != 1 || field.Tag == nil || !isScalarType(field.Type) {
+			continue
+		}
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		gorm := tag.Get("gorm")
+		name, _, _ := strings.Cut(tag.Get("json"), ",")
+		switch name {
+		case "", "-", "id", "created_at", "updated_at", "deleted_at":
+			continue
+		}
+		if model, ok := foreignKeys[field.Names[0].Name]; ok {
+			fields[name] = "belongs_to(" + model + ")"
+			continue
+		}
+		if field.Comment != nil {
+			if source, ok := slugSourceOf(field.Comment.Text()); ok {
+				fields[name] = "slug(" + source + ")"
+				continue
+			}
+		}
+
+		typ := exprName(field.Type)
+		star, pointer := field.Type.(*ast.StarExpr)
+		if pointer {
+			typ = exprName(star.X)
+		}
+		if jsonType, ok := jsonGoTypes[types.ExprString(field.Type)]; ok {
+			typ = jsonType
+		}
+		switch typ {
+		case "string":
+			if strings.Contains(gorm, "type:text") {
+				typ = "text"
+			} else if strings.Contains(gorm, "type:time") {
+				typ = "time"
+			}
+		case "float64":
+			typ = "float"
+		case "time.Time":
+			typ = "datetime"
+		case "types.DateTime":
+			typ = "datetime"
+			if strings.Contains(gorm, "type:date") {
+				typ = "date"
+			}
+		case "Decimal":
+			// Money reads back as a decimal of the same scale
+			_, column, _ := strings.Cut(gorm, "type:")
+			column, _, _ = strings.Cut(column, ";")
+			typ = column
+		case "bool", "int", "uint", "int64", "uint64", "[]string", "[]int", "json":
+		default:
+			if values := enumConstValues(file, typ); len(values) > 0 {
+				typ = "enum(" + strings.Join(values, ",") + ")"
+			} else {
+				typ = "string"
+			}
+		}
+		if pointer && !strings.Contains(gorm, "not null") {
+			typ += "?"
+		}
+		if _, def, ok := strings.Cut(gorm, "default:"); ok {
+			typ += ":default=" + gormDefault(def)
+		}
+		fields[name] = typ
+	}
+	return fields
+}
+
+// gormDefault reads back the value of a gorm default tag, quoting strings
+// as a default modifier value
+func gormDefault(tag string) string {
+	if !strings.HasPrefix(tag, "'") {
+		value, _, _ := strings.Cut(tag, ";")
+		return value
+	}
+	i := 1
+	for i < len(tag) && (tag[i] != '\'' || strings.HasPrefix(tag[i:], "''")) {
+		if tag[i] == '\'' {
+			i++
+		}
+		i++
+	}
+	return `"` + strings.ReplaceAll(tag[1:min(i, len(tag))], "''", "'") + `"`
+}
+
+// enumConstValues returns the values of the string constants of a type
+// declared in file, which are those of an enum field
+func enumConstValues(file *ast.File, typ string) []string {
+	var values []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if vs.Type == nil || exprName(vs.Type) != typ {
+				continue
+			}
+			for _, value := range vs.Values {
+				if lit, ok := value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					v, _ := strconv.Unquote(lit.Value)
+					values = append(values, v)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// exprName renders a type name such as int or time.Time
+func exprName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return exprName(t.X) + "." + t.Sel.Name
+	}
+	return ""
+}
+
+var (
+	interfaceFieldPattern = regexp.MustCompile(`^\s*(\w+)\??:\s*([\w\[\]]+|'.*'|\{.*)`)
+	stringLiteralPattern  = regexp.MustCompile(`'([^']*)'`)
+)
+
+// interfaceFields reads the fields of a TypeScript interface
+func interfaceFields(content, name string) map[string]string {
+	fields := map[string]string{}
+	inside := false
+	for _, line := range splitLines(content) {
+		switch {
+		case strings.HasPrefix(line, "export interface "+name+" "):
+			inside = true
+		case inside && strings.HasPrefix(line, "}"):
+			return fields
+		case inside:
+			m := interfaceFieldPattern.FindStringSubmatch(line)
+			if m == nil || m[1] == "id" || m[1] == "created_at" || m[1] == "updated_at" {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(m[2], attachmentType):
+				fields[m[1]] = "file"
+				continue
+			case strings.HasPrefix(m[2], "{") && strings.HasSuffix(m[2], "}[]"):
+				fields[singularize(m[1])+"_ids"] = "many_to_many(" + toPascalCase(singularize(m[1])) + ")"
+				continue
+			case strings.HasPrefix(m[2], "{"):
+				if _, ok := fields[m[1]+"_id"]; ok {
+					fields[m[1]+"_id"] = "belongs_to(" + toPascalCase(m[1]) + ")"
+				}
+				continue
+			}
+			if _, comment, ok := strings.Cut(line, "//"); ok {
+				if source, ok := slugSourceOf(comment); ok {
+					fields[m[1]] = "slug(" + source + ")"
+					continue
+				}
+			}
+			nullable := ""
+			if strings.HasSuffix(strings.TrimSpace(line), "| null") {
+				nullable = "?"
+			}
+			switch m[2] {
+			case "string[]":
+				fields[m[1]] = "[]string"
+			case "number[]":
+				fields[m[1]] = "[]int"
+			case "Record":
+				fields[m[1]] = "json"
+			case "number":
+				fields[m[1]] = "int" + nullable
+			case "boolean":
+				fields[m[1]] = "bool" + nullable
+			default:
+				if strings.HasPrefix(m[2], "'") {
+					var values []string
+					for _, v := range stringLiteralPattern.FindAllStringSubmatch(m[2], -1) {
+						values = append(values, v[1])
+					}
+					fields[m[1]] = "enum(" + strings.Join(values, ",") + ")" + nullable
+					continue
+				}
+				fields[m[1]] = "string" + nullable
+			}
+		}
+	}
+	return fields
+}
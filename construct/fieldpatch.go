@@ -0,0 +1,1136 @@
+package construct
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// goFieldPatch finds the Go code a set of fields contributes to a generated
+// file by comparing the template rendered without and with them, and applies
+// it to the file on disk through its syntax tree
+type goFieldPatch struct {
+	without *goSource
+	with    *goSource
+}
+
+// newGoFieldPatch parses the two renderings of a template
+func newGoFieldPatch(path string, without, with []byte) (*goFieldPatch, error) {
+	wo, err := parseGoSource(path, without)
+	if err != nil {
+		return nil, err
+	}
+	wi, err := parseGoSource(path, with)
+	if err != nil {
+		return nil, err
+	}
+	return &goFieldPatch{without: wo, with: wi}, nil
+}
+
+// add returns the edits adding the fields to target. Code that cannot be
+// placed, because the surrounding code was changed by hand, is reported as
+// a warning.
+func (p *goFieldPatch) add(target *goSource) ([]sourceEdit, []string) {
+	var edits []sourceEdit
+	var warnings []string
+
+	// Struct fields go after the last plain field, and relations at the end
+	targetStructs := structTypes(target.file)
+	for name, st := range p.newStructFields() {
+		ts, ok := targetStructs[name]
+		if !ok {
+			continue
+		}
+		existing := structFieldNames(ts)
+		var scalars, relations strings.Builder
+		for _, field := range st {
+			if existing[fieldName(field)] {
+				continue
+			}
+			if isScalarType(field.Type) {
+				scalars.WriteString(p.with.fieldLines(field))
+			} else {
+				relations.WriteString(p.with.fieldLines(field))
+			}
+		}
+
+		if scalars.Len() > 0 {
+			offset := target.lineEnd(target.offset(ts.Fields.Opening))
+			for _, field := range ts.Fields.List {
+				if isScalarType(field.Type) {
+					_, offset = target.lines(field)
+				}
+			}
+			edits = append(edits, sourceEdit{start: offset, end: offset, text: scalars.String()})
+		}
+		if relations.Len() > 0 {
+			offset := target.lineStart(target.offset(ts.Fields.Closing))
+			edits = append(edits, sourceEdit{start: offset, end: offset, text: relations.String()})
+		}
+	}
+
+	targetFuncs := funcDecls(target.file)
+	withoutFuncs := funcDecls(p.without.file)
+	for key, fn := range funcDecls(p.with.file) {
+		base, ok := withoutFuncs[key]
+		tfn, found := targetFuncs[key]
+		if !ok || !found || fn.Body == nil || tfn.Body == nil {
+			continue
+		}
+
+		// Elements of composite literals go at the end of the literal
+		baseLits := compositeLits(base.Body)
+		targetLits := compositeLits(tfn.Body)
+		for typ, lits := range compositeLits(fn.Body) {
+			for i, lit := range lits {
+				if i >= len(baseLits[typ]) || i >= len(targetLits[typ]) {
+					continue
+				}
+				known := literalKeys(baseLits[typ][i])
+				tlit := targetLits[typ][i]
+				existing := literalKeys(tlit)
+
+				var text strings.Builder
+				for _, elt := range lit.Elts {
+					key := literalKey(elt)
+					if key != "" && !known[key] && !existing[key] {
+						start, end := p.with.lines(elt)
+						text.Write(p.with.src[start:end])
+					}
+				}
+				if text.Len() == 0 {
+					continue
+				}
+				if target.fset.Position(tlit.Lbrace).Line == target.fset.Position(tlit.Rbrace).Line {
+					warnings = append(warnings, fmt.Sprintf("add the new fields to the %s literal in %s by hand", typ, fn.Name.Name))
+					continue
+				}
+				offset := target.lineStart(target.offset(tlit.Rbrace))
+				edits = append(edits, sourceEdit{start: offset, end: offset, text: text.String()})
+			}
+		}
+
+		// Statements go where the template puts them, before the statement
+		// that follows them
+		edits, warnings = p.addStatements(target, base, fn, tfn, edits, warnings)
+	}
+
+	// Declarations the fields add, such as the type of an enum field, go
+	// after the declaration they follow in the template
+	newDecls := p.newDecls()
+	targetDecls := declsByKey(target.file)
+	offset := len(target.src)
+	for _, decl := range p.with.file.Decls {
+		key := declKey(decl)
+		if tdecl, ok := targetDecls[key]; ok {
+			_, offset = target.lines(tdecl)
+			continue
+		}
+		if newDecls[key] {
+			start, end := p.with.lines(declWithDoc(decl))
+			edits = append(edits, sourceEdit{start: offset, end: offset, text: "\n" + string(p.with.src[start:end])})
+		}
+	}
+
+	imports := p.with.file.Imports
+	for i, imp := range imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if _, ok := p.without.importName(path); ok {
+			continue
+		}
+		if _, ok := target.importName(path); ok {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		pkgName := name
+		if imp.Name != nil {
+			pkgName = imp.Name.Name
+		}
+		var prev, next string
+		line := p.with.fset.Position(imp.Pos()).Line
+		if i > 0 && p.with.fset.Position(imports[i-1].Pos()).Line == line-1 {
+			prev, _ = strconv.Unquote(imports[i-1].Path.Value)
+		}
+		if i+1 < len(imports) && p.with.fset.Position(imports[i+1].Pos()).Line == line+1 {
+			next, _ = strconv.Unquote(imports[i+1].Path.Value)
+		}
+		edits = append(edits, target.addImportBeside(path, pkgName, name, prev, next))
+	}
+
+	edits = append(edits, p.docEdits(target, func(line string, ins docInsert) string {
+		if listIndex(line[len(ins.prefix):len(line)-len(ins.suffix)], ins.text) >= 0 {
+			return line
+		}
+		return line[:len(line)-len(ins.suffix)] + ins.text + ins.suffix
+	})...)
+	edits = append(edits, p.addDocLines(target)...)
+
+	return edits, warnings
+}
+
+// addStatements adds the statements the fields add to a function body
+func (p *goFieldPatch) addStatements(target *goSource, base, fn, tfn *ast.FuncDecl, edits []sourceEdit, warnings []string) ([]sourceEdit, []string) {
+	stmts := fn.Body.List
+	ops := diffLines(statementTexts(p.without, base.Body.List), statementTexts(p.with, stmts))
+	baseLines := map[string]bool{}
+	for _, line := range splitLines(p.without.text(base.Body)) {
+		baseLines[strings.TrimSpace(line)] = true
+	}
+	targetTexts := statementTexts(target, tfn.Body.List)
+
+	j, searchFrom := 0, 0
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			j++
+			k++
+			continue
+		}
+
+		first, changed := j, false
+		for k < len(ops) && ops[k].kind != ' ' {
+			if ops[k].kind == '+' {
+				j++
+			} else {
+				changed = true
+			}
+			k++
+		}
+		// Statements the fields change rather than add are left alone
+		if changed || j == first {
+			continue
+		}
+
+		// Take the statements with the comments above them, leaving out
+		// lines that are there without the fields too but keeping a blank
+		// line that separates them
+		start := p.with.lineEnd(p.with.offset(fn.Body.Lbrace))
+		if first > 0 {
+			_, start = p.with.lines(stmts[first-1])
+		}
+		_, end := p.with.lines(stmts[j-1])
+		lines := splitLines(string(p.with.src[start:end]))
+		blank := false
+		for len(lines) > 0 && baseLines[strings.TrimSpace(lines[0])] {
+			blank = blank || strings.TrimSpace(lines[0]) == ""
+			lines = lines[1:]
+		}
+		if blank {
+			lines = append([]string{""}, lines...)
+		}
+		text := strings.Join(lines, "\n") + "\n"
+
+		if j == len(stmts) {
+			offset := target.lineStart(target.offset(tfn.Body.Rbrace))
+			edits = append(edits, sourceEdit{start: offset, end: offset, text: text})
+			continue
+		}
+
+		next := statementTexts(p.with, stmts[j:j+1])[0]
+		found := false
+		for i := searchFrom; i < len(targetTexts); i++ {
+			if targetTexts[i] != next {
+				continue
+			}
+			offset := target.lineEnd(target.offset(tfn.Body.Lbrace))
+			if i > 0 {
+				_, offset = target.lines(tfn.Body.List[i-1])
+			}
+			edits = append(edits, sourceEdit{start: offset, end: offset, text: text})
+			searchFrom, found = i, true
+			break
+		}
+		if !found {
+			warnings = append(warnings, fmt.Sprintf("could not place new statements in %s, add them by hand:\n%s", fn.Name.Name, text))
+		}
+	}
+	return edits, warnings
+}
+
+// remove returns the edits removing the fields from target, identified by
+// the struct fields and literal keys they add and the fields statements
+// refer to
+func (p *goFieldPatch) remove(target *goSource, fieldNames []string) []sourceEdit {
+	var edits []sourceEdit
+
+	targetStructs := structTypes(target.file)
+	for name, st := range p.newStructFields() {
+		ts, ok := targetStructs[name]
+		if !ok {
+			continue
+		}
+		names := map[string]bool{}
+		for _, field := range st {
+			names[fieldName(field)] = true
+		}
+		for _, field := range ts.Fields.List {
+			if names[fieldName(field)] {
+				start, end := target.lines(fieldWithDoc(field))
+				edits = append(edits, sourceEdit{start: start, end: end})
+			}
+		}
+	}
+
+	referenced := map[string]bool{}
+	for _, name := range fieldNames {
+		referenced[name] = true
+	}
+	methods := calledMethods(p.without.file)
+
+	// Helpers the fields add stay while other fields still call them
+	targetDecls := declsByKey(target.file)
+	for key := range p.newDecls() {
+		decl, ok := targetDecls[key]
+		if !ok {
+			continue
+		}
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && calledWithout(target, fn.Name.Name, referenced, methods) {
+			continue
+		}
+		start, end := target.lines(declWithDoc(decl))
+		if start > 0 && strings.TrimSpace(string(target.src[target.lineStart(start-1):start])) == "" {
+			start = target.lineStart(start - 1)
+		}
+		edits = append(edits, sourceEdit{start: start, end: end})
+	}
+
+	targetFuncs := funcDecls(target.file)
+	withoutFuncs := funcDecls(p.without.file)
+	for key, fn := range funcDecls(p.with.file) {
+		base, ok := withoutFuncs[key]
+		tfn, found := targetFuncs[key]
+		if !ok || !found || fn.Body == nil || tfn.Body == nil {
+			continue
+		}
+
+		baseLits := compositeLits(base.Body)
+		targetLits := compositeLits(tfn.Body)
+		for typ, lits := range compositeLits(fn.Body) {
+			// Keys a literal of the type has without the fields are kept,
+			// even when the fields add literals of the same type
+			known := map[string]bool{}
+			for _, lit := range baseLits[typ] {
+				for key := range literalKeys(lit) {
+					known[key] = true
+				}
+			}
+			keys := map[string]bool{}
+			for _, lit := range lits {
+				for key := range literalKeys(lit) {
+					if !known[key] {
+						keys[key] = true
+					}
+				}
+			}
+			for _, tlit := range targetLits[typ] {
+				for _, elt := range tlit.Elts {
+					if keys[literalKey(elt)] {
+						start, end := target.lines(elt)
+						edits = append(edits, sourceEdit{start: start, end: end})
+					}
+				}
+			}
+		}
+
+		// Statements referring to the fields are removed along with the
+		// comments that come with them. The rules of removed fields are not
+		// read back, so the statements are not compared to the template.
+		comments := map[string]bool{}
+		for _, line := range splitLines(p.with.text(fn.Body)) {
+			if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "//") {
+				comments[trimmed] = true
+			}
+		}
+		for _, line := range splitLines(p.without.text(base.Body)) {
+			delete(comments, strings.TrimSpace(line))
+		}
+		// The blank line before statements ending the body goes with them
+		trailing := len(tfn.Body.List)
+		for trailing > 0 && refersToField(tfn.Body.List[trailing-1], referenced, methods) {
+			trailing--
+		}
+		for i, stmt := range tfn.Body.List {
+			if !refersToField(stmt, referenced, methods) {
+				continue
+			}
+			start, end := target.lines(stmt)
+			limit := target.lineEnd(target.offset(tfn.Body.Lbrace))
+			if i > 0 {
+				_, limit = target.lines(tfn.Body.List[i-1])
+			}
+			for start > limit {
+				prev := target.lineStart(start - 1)
+				if !comments[strings.TrimSpace(string(target.src[prev:start]))] {
+					break
+				}
+				start = prev
+			}
+			if i == trailing && i > 0 && start > limit {
+				if prev := target.lineStart(start - 1); strings.TrimSpace(string(target.src[prev:start])) == "" {
+					start = prev
+				}
+			}
+			edits = append(edits, sourceEdit{start: start, end: end})
+		}
+	}
+
+	edits = append(edits, p.docEdits(target, func(line string, ins docInsert) string {
+		i := listIndex(line[len(ins.prefix):len(line)-len(ins.suffix)], ins.text)
+		if i < 0 {
+			return line
+		}
+		i += len(ins.prefix)
+		return line[:i] + line[i+len(ins.text):]
+	})...)
+	edits = append(edits, p.docLineEdits(target, func(string) string { return "" })...)
+
+	return edits
+}
+
+// calledWithout reports whether a function of target is called by a
+// statement that does not refer to the named fields
+func calledWithout(target *goSource, name string, fields, methods map[string]bool) bool {
+	for _, fn := range funcDecls(target.file) {
+		if fn.Body == nil || fn.Name.Name == name {
+			continue
+		}
+		for _, stmt := range fn.Body.List {
+			if refersToField(stmt, fields, methods) {
+				continue
+			}
+			called := false
+			ast.Inspect(stmt, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					ident, ok := call.Fun.(*ast.Ident)
+					called = ok && ident.Name == name
+				}
+				return !called
+			})
+			if called {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rename returns the edits renaming a field in the structs and functions the
+// template generates for it: struct fields and their json tags, literal
+// keys, selectors and column name strings
+func (p *goFieldPatch) rename(target *goSource, oldField, newField TemplateField) []sourceEdit {
+	var edits []sourceEdit
+	replace := func(node ast.Node, text string) {
+		edits = append(edits, sourceEdit{start: target.offset(node.Pos()), end: target.offset(node.End()), text: text})
+	}
+	goNames := map[string]string{oldField.FieldName: newField.FieldName}
+	for _, name := range oldField.filterGoNames() {
+		goNames[name] = newField.FieldName + strings.TrimPrefix(name, oldField.FieldName)
+	}
+
+	targetStructs := structTypes(target.file)
+	for name := range p.newStructFields() {
+		ts, ok := targetStructs[name]
+		if !ok {
+			continue
+		}
+		for _, field := range ts.Fields.List {
+			if len(field.Names) != 1 || goNames[field.Names[0].Name] == "" {
+				continue
+			}
+			replace(field.Names[0], goNames[field.Names[0].Name])
+			if field.Tag != nil {
+				tag := strings.Replace(field.Tag.Value, `json:"`+oldField.Name+`"`, `json:"`+newField.Name+`"`, 1)
+				tag = strings.Replace(tag, `json:"`+oldField.Name+`,`, `json:"`+newField.Name+`,`, 1)
+				if tag != field.Tag.Value {
+					replace(field.Tag, tag)
+				}
+			}
+		}
+	}
+
+	targetFuncs := funcDecls(target.file)
+	withFuncs, withoutFuncs := funcDecls(p.with.file), funcDecls(p.without.file)
+	oldWords := " " + strings.ToLower(oldField.Label) + " "
+	newWords := " " + strings.ToLower(newField.Label) + " "
+	for key := range p.changedFuncs() {
+		tfn, ok := targetFuncs[key]
+		if !ok || tfn.Body == nil {
+			continue
+		}
+
+		// Comments the field adds name it by its label
+		comments := map[string]bool{}
+		for _, line := range splitLines(p.with.text(withFuncs[key].Body)) {
+			if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "//") {
+				comments[trimmed] = true
+			}
+		}
+		for _, line := range splitLines(p.without.text(withoutFuncs[key].Body)) {
+			delete(comments, strings.TrimSpace(line))
+		}
+		for _, group := range target.file.Comments {
+			if group.Pos() < tfn.Body.Lbrace || group.End() > tfn.Body.Rbrace {
+				continue
+			}
+			for _, c := range group.List {
+				// A space is added so the label may end the comment
+				text := strings.TrimSuffix(strings.Replace(c.Text+" ", oldWords, newWords, 1), " ")
+				if comments[c.Text] && text != c.Text {
+					replace(c, text)
+				}
+			}
+		}
+
+		ast.Inspect(tfn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				if renamed, ok := goNames[n.Sel.Name]; ok {
+					replace(n.Sel, renamed)
+				}
+			case *ast.KeyValueExpr:
+				if ident, ok := n.Key.(*ast.Ident); ok && goNames[ident.Name] != "" {
+					replace(ident, goNames[ident.Name])
+				}
+			case *ast.BasicLit:
+				// Column names and filter parameters
+				value, err := strconv.Unquote(n.Value)
+				if n.Kind != token.STRING || err != nil {
+					break
+				}
+				if renamed, ok := renameColumn(value, oldField.Name, newField.Name); ok {
+					replace(n, strconv.Quote(renamed))
+				}
+			}
+			return true
+		})
+	}
+
+	edits = append(edits, p.renameTypes(target, oldField, newField)...)
+
+	edits = append(edits, p.docEdits(target, func(line string, ins docInsert) string {
+		i := listIndex(line[len(ins.prefix):len(line)-len(ins.suffix)], ins.text)
+		if i < 0 {
+			return line
+		}
+		i += len(ins.prefix)
+		renamed := strings.ReplaceAll(ins.text, oldField.Name, newField.Name)
+		return line[:i] + renamed + line[i+len(ins.text):]
+	})...)
+	edits = append(edits, p.docLineEdits(target, func(text string) string {
+		text = strings.ReplaceAll(text, oldField.Name, newField.Name)
+		return strings.Replace(text, `"`+oldField.Label+" ", `"`+newField.Label+" ", 1)
+	})...)
+
+	return edits
+}
+
+// renameTypes renames the types the field declares, such as the type of an
+// enum field, along with the constants named after them and their doc
+// comments, and the references of other files to them
+func (p *goFieldPatch) renameTypes(target *goSource, oldField, newField TemplateField) []sourceEdit {
+	var edits []sourceEdit
+	targetDecls := declsByKey(target.file)
+	oldTypes := map[string]bool{}
+	for key := range p.newDecls() {
+		if name, ok := strings.CutPrefix(key, "type "); ok {
+			oldTypes[name] = true
+		}
+	}
+	withoutTypes := modelTypes(p.without)
+	for name := range modelTypes(p.with) {
+		if !withoutTypes[name] {
+			oldTypes[name] = true
+		}
+	}
+	for oldType := range oldTypes {
+		if !strings.HasSuffix(oldType, oldField.FieldName) {
+			continue
+		}
+		newType := strings.TrimSuffix(oldType, oldField.FieldName) + newField.FieldName
+
+		ast.Inspect(target.file, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok || !strings.HasPrefix(ident.Name, oldType) {
+				return true
+			}
+			if rest := ident.Name[len(oldType):]; rest == "" || !unicode.IsLower(rune(rest[0])) {
+				edits = append(edits, sourceEdit{
+					start: target.offset(ident.Pos()),
+					end:   target.offset(ident.End()),
+					text:  newType + rest,
+				})
+			}
+			return true
+		})
+
+		oldWords := " " + strings.ToLower(oldField.Label) + " "
+		newWords := " " + strings.ToLower(newField.Label) + " "
+		for _, decl := range targetDecls {
+			var doc *ast.CommentGroup
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				doc = d.Doc
+			case *ast.GenDecl:
+				doc = d.Doc
+			}
+			if doc == nil || !strings.Contains(doc.Text(), oldType) {
+				continue
+			}
+			for _, c := range doc.List {
+				text := strings.ReplaceAll(c.Text, oldType, newType)
+				text = strings.Replace(text, oldWords, newWords, 1)
+				if text != c.Text {
+					edits = append(edits, sourceEdit{start: target.offset(c.Pos()), end: target.offset(c.End()), text: text})
+				}
+			}
+		}
+	}
+	return edits
+}
+
+// docInsert is text the fields insert into a line of a doc comment, such as
+// the sortable fields listed in the swagger comments
+type docInsert struct {
+	prefix, text, suffix string
+}
+
+// docLine is a line the fields add to a doc comment, such as the swagger
+// parameters of a range filter, placed before the line that follows it
+type docLine struct {
+	text, before string
+}
+
+// docChanges returns the text the fields insert into the lines of the doc
+// comments of each function and the lines they add to them
+func (p *goFieldPatch) docChanges() (map[string][]docInsert, map[string][]docLine) {
+	inserts := map[string][]docInsert{}
+	added := map[string][]docLine{}
+	without := funcDecls(p.without.file)
+	for key, fn := range funcDecls(p.with.file) {
+		base, ok := without[key]
+		if !ok || fn.Doc == nil || base.Doc == nil {
+			continue
+		}
+
+		// A line the fields change is paired with the line it replaces
+		ops := diffLines(commentTexts(base.Doc), commentTexts(fn.Doc))
+		var replaced []string
+		for i, op := range ops {
+			switch op.kind {
+			case ' ':
+				replaced = nil
+			case '-':
+				replaced = append(replaced, op.line)
+			case '+':
+				if len(replaced) > 0 {
+					if ins, ok := lineInsert(replaced[0], op.line); ok {
+						inserts[key] = append(inserts[key], ins)
+					}
+					replaced = replaced[1:]
+					continue
+				}
+				line := docLine{text: op.line}
+				for _, next := range ops[i+1:] {
+					if next.kind == ' ' {
+						line.before = next.line
+						break
+					}
+				}
+				added[key] = append(added[key], line)
+			}
+		}
+	}
+	return inserts, added
+}
+
+// lineInsert returns the text inserted into old to make line, if line only
+// grew
+func lineInsert(old, line string) (docInsert, bool) {
+	if len(line) <= len(old) {
+		return docInsert{}, false
+	}
+	prefix := 0
+	for prefix < len(old) && line[prefix] == old[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && line[len(line)-1-suffix] == old[len(old)-1-suffix] {
+		suffix++
+	}
+	if prefix+suffix != len(old) {
+		return docInsert{}, false // The line changed rather than grew
+	}
+	return docInsert{
+		prefix: old[:prefix],
+		text:   line[prefix : len(line)-suffix],
+		suffix: old[prefix:],
+	}, true
+}
+
+// commentTexts returns the lines of a comment group
+func commentTexts(doc *ast.CommentGroup) []string {
+	texts := make([]string, len(doc.List))
+	for i, c := range doc.List {
+		texts[i] = c.Text
+	}
+	return texts
+}
+
+// docEdits rewrites the doc comment lines of target that the fields extend
+func (p *goFieldPatch) docEdits(target *goSource, change func(line string, ins docInsert) string) []sourceEdit {
+	var edits []sourceEdit
+	targetFuncs := funcDecls(target.file)
+	docInserts, _ := p.docChanges()
+	for key, inserts := range docInserts {
+		tfn, ok := targetFuncs[key]
+		if !ok || tfn.Doc == nil {
+			continue
+		}
+		for _, c := range tfn.Doc.List {
+			line := c.Text
+			for _, ins := range inserts {
+				if len(line) >= len(ins.prefix)+len(ins.suffix) &&
+					strings.HasPrefix(line, ins.prefix) && strings.HasSuffix(line, ins.suffix) {
+					line = change(line, ins)
+				}
+			}
+			if line != c.Text {
+				edits = append(edits, sourceEdit{start: target.offset(c.Pos()), end: target.offset(c.End()), text: line})
+			}
+		}
+	}
+	return edits
+}
+
+// addDocLines inserts the doc comment lines the fields add into target,
+// before the line that follows them
+func (p *goFieldPatch) addDocLines(target *goSource) []sourceEdit {
+	var edits []sourceEdit
+	targetFuncs := funcDecls(target.file)
+	_, added := p.docChanges()
+	for key, lines := range added {
+		tfn, ok := targetFuncs[key]
+		if !ok || tfn.Doc == nil {
+			continue
+		}
+		existing := commentTexts(tfn.Doc)
+		for _, line := range lines {
+			if containsString(existing, line.text) {
+				continue
+			}
+			offset := target.lineEnd(target.offset(tfn.Doc.End()))
+			for _, c := range tfn.Doc.List {
+				if c.Text == line.before {
+					offset = target.lineStart(target.offset(c.Pos()))
+					break
+				}
+			}
+			edits = append(edits, sourceEdit{start: offset, end: offset, text: line.text + "\n"})
+		}
+	}
+	return edits
+}
+
+// docLineEdits rewrites the doc comment lines of target that the fields
+// add, dropping those change returns "" for
+func (p *goFieldPatch) docLineEdits(target *goSource, change func(text string) string) []sourceEdit {
+	var edits []sourceEdit
+	targetFuncs := funcDecls(target.file)
+	_, added := p.docChanges()
+	for key, lines := range added {
+		tfn, ok := targetFuncs[key]
+		if !ok || tfn.Doc == nil {
+			continue
+		}
+		for _, c := range tfn.Doc.List {
+			for _, line := range lines {
+				if c.Text != line.text {
+					continue
+				}
+				if text := change(c.Text); text == "" {
+					start, end := target.lines(c)
+					edits = append(edits, sourceEdit{start: start, end: end})
+				} else if text != c.Text {
+					edits = append(edits, sourceEdit{start: target.offset(c.Pos()), end: target.offset(c.End()), text: text})
+				}
+			}
+		}
+	}
+	return edits
+}
+
+// listIndex finds item in a list such as "title,body," where it starts at
+// the beginning or after a separator, or returns -1
+func listIndex(list, item string) int {
+	for offset := 0; ; {
+		i := strings.Index(list[offset:], item)
+		if i < 0 {
+			return -1
+		}
+		i += offset
+		if i == 0 || !isWordByte(list[i-1]) {
+			return i
+		}
+		offset = i + 1
+	}
+}
+
+// isWordByte reports whether c can be part of an identifier
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// newDecls returns the keys of the top-level declarations the fields add
+func (p *goFieldPatch) newDecls() map[string]bool {
+	without := declsByKey(p.without.file)
+	added := map[string]bool{}
+	for key := range declsByKey(p.with.file) {
+		if _, ok := without[key]; !ok {
+			added[key] = true
+		}
+	}
+	return added
+}
+
+// declsByKey returns the top-level declarations of a file other than
+// imports by declKey
+func declsByKey(file *ast.File) map[string]ast.Decl {
+	decls := map[string]ast.Decl{}
+	for _, decl := range file.Decls {
+		if key := declKey(decl); key != "" {
+			decls[key] = decl
+		}
+	}
+	return decls
+}
+
+// declKey identifies a declaration: a function by its receiver and name as
+// in funcDecls, a type, const or var declaration by its first name
+func declKey(decl ast.Decl) string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			return types.ExprString(d.Recv.List[0].Type) + "." + d.Name.Name
+		}
+		return d.Name.Name
+	case *ast.GenDecl:
+		if len(d.Specs) == 0 {
+			return ""
+		}
+		switch spec := d.Specs[0].(type) {
+		case *ast.TypeSpec:
+			return "type " + spec.Name.Name
+		case *ast.ValueSpec:
+			return d.Tok.String() + " " + spec.Names[0].Name
+		}
+	}
+	return ""
+}
+
+// docSpan spans a declaration and its doc comment
+type docSpan struct {
+	pos, end token.Pos
+}
+
+func (s docSpan) Pos() token.Pos { return s.pos }
+func (s docSpan) End() token.Pos { return s.end }
+
+// declWithDoc returns a node spanning a declaration and its doc comment
+func declWithDoc(decl ast.Decl) ast.Node {
+	span := docSpan{decl.Pos(), decl.End()}
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Doc != nil {
+			span.pos = d.Doc.Pos()
+		}
+	case *ast.GenDecl:
+		if d.Doc != nil {
+			span.pos = d.Doc.Pos()
+		}
+	}
+	return span
+}
+
+// newStructFields returns the fields each struct gains with the fields
+func (p *goFieldPatch) newStructFields() map[string][]*ast.Field {
+	added := map[string][]*ast.Field{}
+	without := structTypes(p.without.file)
+	for name, st := range structTypes(p.with.file) {
+		base, ok := without[name]
+		if !ok {
+			continue
+		}
+		known := structFieldNames(base)
+		for _, field := range st.Fields.List {
+			if !known[fieldName(field)] {
+				added[name] = append(added[name], field)
+			}
+		}
+	}
+	return added
+}
+
+// changedFuncs returns the functions whose body changes with the fields
+func (p *goFieldPatch) changedFuncs() map[string]bool {
+	changed := map[string]bool{}
+	without := funcDecls(p.without.file)
+	for key, fn := range funcDecls(p.with.file) {
+		base, ok := without[key]
+		if ok && fn.Body != nil && base.Body != nil &&
+			normalizeSpace(p.with.text(fn.Body)) != normalizeSpace(p.without.text(base.Body)) {
+			changed[key] = true
+		}
+	}
+	return changed
+}
+
+// fieldLines returns the source lines of a struct field with its comments
+func (s *goSource) fieldLines(field *ast.Field) string {
+	start, end := s.lines(fieldWithDoc(field))
+	return string(s.src[start:end])
+}
+
+// docNode spans a struct field and its doc comment
+type docNode struct {
+	*ast.Field
+}
+
+// Pos starts at the doc comment, if any
+func (n docNode) Pos() token.Pos {
+	if n.Doc != nil {
+		return n.Doc.Pos()
+	}
+	return n.Field.Pos()
+}
+
+// fieldWithDoc returns a node spanning a struct field and its doc comment
+func fieldWithDoc(field *ast.Field) ast.Node {
+	return docNode{field}
+}
+
+// structTypes returns the struct types declared in a file by name
+func structTypes(file *ast.File) map[string]*ast.StructType {
+	structs := map[string]*ast.StructType{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok {
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				structs[spec.Name.Name] = st
+			}
+		}
+		return true
+	})
+	return structs
+}
+
+// structFieldNames returns the names of the fields of a struct
+func structFieldNames(st *ast.StructType) map[string]bool {
+	names := map[string]bool{}
+	for _, field := range st.Fields.List {
+		names[fieldName(field)] = true
+	}
+	return names
+}
+
+// fieldName names a struct field, using the type of embedded fields
+func fieldName(field *ast.Field) string {
+	if len(field.Names) == 0 {
+		return types.ExprString(field.Type)
+	}
+	var names []string
+	for _, name := range field.Names {
+		names = append(names, name.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// isScalarType reports whether a field holds a value rather than a relation
+// or an attachment
+func isScalarType(expr ast.Expr) bool {
+	if types.ExprString(expr) == "*storage.Attachment" {
+		return false
+	}
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if ts, ok := identTypeSpec(t); ok {
+			return isScalarType(ts.Type)
+		}
+		// Decimal is declared in models/decimal.go
+		return types.Universe.Lookup(t.Name) != nil || t.Name == "Decimal"
+	case *ast.SelectorExpr:
+		return true
+	case *ast.StarExpr:
+		return isScalarType(t.X)
+	case *ast.IndexExpr:
+		// A Nullable of an update request
+		return isScalarType(t.Index)
+	case *ast.ArrayType, *ast.MapType:
+		// Array and JSON fields, unlike the []*Model of a relation
+		_, ok := jsonGoTypes[types.ExprString(expr)]
+		return ok
+	}
+	return false
+}
+
+// identTypeSpec returns the declaration of a type declared in the same file
+func identTypeSpec(ident *ast.Ident) (*ast.TypeSpec, bool) {
+	if ident.Obj == nil || ident.Obj.Kind != ast.Typ {
+		return nil, false
+	}
+	ts, ok := ident.Obj.Decl.(*ast.TypeSpec)
+	return ts, ok
+}
+
+// funcDecls returns the functions of a file keyed by receiver and name
+func funcDecls(file *ast.File) map[string]*ast.FuncDecl {
+	funcs := map[string]*ast.FuncDecl{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		key := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			key = types.ExprString(fn.Recv.List[0].Type) + "." + key
+		}
+		funcs[key] = fn
+	}
+	return funcs
+}
+
+// compositeLits returns the typed composite literals in a node, grouped by
+// type in source order
+func compositeLits(node ast.Node) map[string][]*ast.CompositeLit {
+	lits := map[string][]*ast.CompositeLit{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.CompositeLit); ok && lit.Type != nil {
+			typ := types.ExprString(lit.Type)
+			lits[typ] = append(lits[typ], lit)
+		}
+		return true
+	})
+	return lits
+}
+
+// literalKeys returns the keys of a keyed composite literal
+func literalKeys(lit *ast.CompositeLit) map[string]bool {
+	keys := map[string]bool{}
+	for _, elt := range lit.Elts {
+		if key := literalKey(elt); key != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// literalKey returns the key of a composite literal element. Elements
+// without one, such as the models of a module, are their own key unless they
+// hold literals with elements, which ExprString abbreviates.
+func literalKey(elt ast.Expr) string {
+	if kv, ok := elt.(*ast.KeyValueExpr); ok {
+		return types.ExprString(kv.Key)
+	}
+	nested := false
+	ast.Inspect(elt, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.CompositeLit); ok && len(lit.Elts) > 0 {
+			nested = true
+		}
+		return !nested
+	})
+	if nested {
+		return ""
+	}
+	return types.ExprString(elt)
+}
+
+// statementTexts returns statements with their whitespace normalized and
+// the elements of composite literals left out, since literals gain elements
+// in place rather than changing the statement
+func statementTexts(s *goSource, stmts []ast.Stmt) []string {
+	texts := make([]string, len(stmts))
+	for i, stmt := range stmts {
+		var text strings.Builder
+		last := s.offset(stmt.Pos())
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			text.Write(s.src[last : s.offset(lit.Lbrace)+1])
+			last = s.offset(lit.Rbrace)
+			return false
+		})
+		text.Write(s.src[last:s.offset(stmt.End())])
+		texts[i] = normalizeSpace(text.String())
+	}
+	return texts
+}
+
+// modelTypes returns the names of the models package a source refers to,
+// such as the type of an enum field in models.PostStatus
+func modelTypes(s *goSource) map[string]bool {
+	names := map[string]bool{}
+	ast.Inspect(s.file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "models" {
+				names[sel.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// calledMethods returns the names of the methods a file calls, e.g. Status
+// for ctx.Status(http.StatusNoContent)
+func calledMethods(file *ast.File) map[string]bool {
+	names := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+				names[sel.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// normalizeSpace collapses runs of whitespace into single spaces
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// refersToField reports whether a node selects one of the named fields or
+// names it in a string, as Preload does. Composite literals are left out,
+// since their elements are removed on their own, and so are calls of
+// methods, such as ctx.Status, that share a field's name.
+func refersToField(node ast.Node, names, methods map[string]bool) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.CompositeLit:
+			return false
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && methods[sel.Sel.Name] {
+				found = refersToField(sel.X, names, methods)
+				for _, arg := range n.Args {
+					found = found || refersToField(arg, names, methods)
+				}
+				return false
+			}
+		case *ast.SelectorExpr:
+			found = names[n.Sel.Name]
+		case *ast.BasicLit:
+			value, err := strconv.Unquote(n.Value)
+			found = n.Kind == token.STRING && err == nil && names[value]
+		}
+		return !found
+	})
+	return found
+}
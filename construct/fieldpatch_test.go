@@ -0,0 +1,154 @@
+package construct
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// userEdits are hand edits outside the generated field markers, which
+// patching the fields must keep
+var userEdits = map[string]string{
+	"api/posts/service.go":          "\n// Published returns whether a post is visible\nfunc Published() bool { return true }\n",
+	"vue/app/posts/pages/index.vue": "\n<style scoped>\n.posts { margin: 0 }\n</style>\n",
+}
+
+// newFieldProject generates a Post resource with fields in a new project
+// and returns its root
+func newFieldProject(t *testing.T, fields ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.25\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "api", "init.go"), []byte(initSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := NewTemplateData("Post", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateBackend(root, data, GenerateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateFrontend(root, data, GenerateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// editProject appends the user edits to the generated files
+func editProject(t *testing.T, root string) {
+	t.Helper()
+	for rel, edit := range userEdits {
+		path := filepath.Join(root, rel)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, append(content, edit...), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// projectSources reads the Go and Vue/TS files of a project by their path
+// relative to it
+func projectSources(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	for _, dir := range []string{"api", "vue"} {
+		err := filepath.Walk(filepath.Join(root, dir), func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(root, path)
+			files[filepath.ToSlash(rel)] = string(content)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return files
+}
+
+// checkSameSources fails the test unless both projects hold the same files
+func checkSameSources(t *testing.T, got, want string) {
+	t.Helper()
+	gotFiles, wantFiles := projectSources(t, got), projectSources(t, want)
+	for rel, content := range wantFiles {
+		patched, ok := gotFiles[rel]
+		if !ok {
+			t.Errorf("%s is missing", rel)
+			continue
+		}
+		if patched != content {
+			t.Errorf("%s differs from a fresh generate:\n%s", rel, unifiedDiff(rel, rel, content, patched))
+		}
+	}
+	for rel := range gotFiles {
+		if _, ok := wantFiles[rel]; !ok {
+			t.Errorf("%s should not exist", rel)
+		}
+	}
+}
+
+func TestPatchFieldsMatchesGenerate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // No user template overrides
+	base := []string{"title:string", "summary:text"}
+	extra := []string{"views:int", "published:bool", "status:enum(draft,published)", "category:belongs_to", "cover:image"}
+
+	tests := []struct {
+		name   string
+		fields []string // Fields generated first
+		patch  func(root string) error
+		want   []string // Fields of the fresh generate to compare with
+	}{
+		{
+			name:   "add",
+			fields: base,
+			patch: func(root string) error {
+				return AddFields(root, "Post", extra, GenerateOptions{})
+			},
+			want: append(append([]string{}, base...), extra...),
+		},
+		{
+			name:   "remove",
+			fields: append(append([]string{}, base...), extra...),
+			patch: func(root string) error {
+				return RemoveFields(root, "Post", []string{"views", "published", "status", "category_id", "cover"}, GenerateOptions{})
+			},
+			want: base,
+		},
+		{
+			name:   "rename",
+			fields: base,
+			patch: func(root string) error {
+				return RenameField(root, "Post", "summary", "excerpt", GenerateOptions{})
+			},
+			want: []string{"title:string", "excerpt:text"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newFieldProject(t, tt.fields...)
+			editProject(t, root)
+			if err := tt.patch(root); err != nil {
+				t.Fatal(err)
+			}
+
+			fresh := newFieldProject(t, tt.want...)
+			editProject(t, fresh)
+			checkSameSources(t, root, fresh)
+		})
+	}
+}
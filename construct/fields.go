@@ -0,0 +1,860 @@
+package construct
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field definitions passed to generate follow the grammar
+//
+//	name:type[?][=default][:modifier]...
+//
+// where a trailing "?" makes the field nullable and modifiers are one of
+// required, unique, index, min=N, max=N or default=VALUE; type=VALUE is
+// shorthand for the default modifier. Values may be quoted with single or
+// double quotes to include ":" characters, e.g.
+//
+//	title:string:required:unique:max=255
+//	bio:text?
+//	rank:int=10
+//	status:enum(draft,published,archived):default=draft
+//	category:belongs_to:required
+//	avatar:image:max=2
+//	published_on:date:required
+//	tags:[]string:max=10
+//	price:money(EUR):required
+//	slug:slug(title)
+
+// fieldTypes lists the field types understood by the generator
+var fieldTypes = []string{
+	"string", "text", "email", "url",
+	"int", "uint", "int64", "uint64", "float", "float64",
+	"bool", "boolean",
+	"date", "datetime", "time",
+	"[]string", "[]int", "json",
+	"money", "decimal",
+	"slug",
+	"enum",
+	"belongs_to", "many_to_many",
+	"image", "file",
+}
+
+// fieldModifiers lists the modifiers that may follow a field type
+var fieldModifiers = []string{"required", "unique", "index", "min", "max", "default"}
+
+var fieldNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedFieldNames lists the columns every model gets from gorm.Model
+var reservedFieldNames = []string{"id", "created_at", "updated_at", "deleted_at"}
+
+// enumValuePattern matches the values of an enum field
+var enumValuePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// FieldError describes a malformed field definition
+type FieldError struct {
+	Arg        string // Full argument as typed, e.g. "title:strng"
+	Index      int    // 1-based position of the argument in the field list
+	Column     int    // 1-based column of the offending token within Arg
+	Token      string // Offending token
+	Message    string
+	Suggestion string
+}
+
+// Error renders the error with a caret pointing at the offending token
+func (e *FieldError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "field #%d %q: %s", e.Index, e.Arg, e.Message)
+	if e.Suggestion != "" {
+		fmt.Fprintf(&b, " (%s)", e.Suggestion)
+	}
+
+	width := len(e.Token)
+	if width == 0 {
+		width = 1
+	}
+	fmt.Fprintf(&b, "\n      %s\n      %s%s", e.Arg, strings.Repeat(" ", max(e.Column-1, 0)), strings.Repeat("^", width))
+
+	return b.String()
+}
+
+// fieldToken is a ":"-separated segment of a field definition
+type fieldToken struct {
+	text   string
+	column int
+}
+
+// splitFieldTokens splits a field definition on ":" while respecting quotes
+// and parentheses, keeping track of the column each token starts at
+func splitFieldTokens(arg string) ([]fieldToken, error) {
+	var tokens []fieldToken
+	var quote byte
+	depth := 0
+	start := 0
+	quoteStart := 0
+
+	for i := 0; i < len(arg); i++ {
+		c := arg[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			quoteStart = i
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ':' && depth == 0:
+			tokens = append(tokens, fieldToken{text: arg[start:i], column: start + 1})
+			start = i + 1
+		}
+	}
+
+	if quote != 0 {
+		return nil, &FieldError{
+			Column:     quoteStart + 1,
+			Token:      arg[quoteStart:],
+			Message:    "unterminated quoted value",
+			Suggestion: fmt.Sprintf("add a closing %c", quote),
+		}
+	}
+
+	tokens = append(tokens, fieldToken{text: arg[start:], column: start + 1})
+	return tokens, nil
+}
+
+// checkReservedFieldName rejects the names of the columns every model has
+func checkReservedFieldName(name string) error {
+	if containsString(reservedFieldNames, toSnakeCase(name)) {
+		return fmt.Errorf("field name %q is a built-in column", name)
+	}
+	return nil
+}
+
+// parseField parses a single field definition into a TemplateField
+func parseField(arg string) (TemplateField, error) {
+	tokens, err := splitFieldTokens(arg)
+	if err != nil {
+		return TemplateField{}, err
+	}
+
+	name := tokens[0]
+	if name.text == "" {
+		return TemplateField{}, &FieldError{
+			Column:     1,
+			Message:    "missing field name",
+			Suggestion: "expected name:type, e.g. title:string",
+		}
+	}
+	if !fieldNamePattern.MatchString(name.text) {
+		return TemplateField{}, &FieldError{
+			Column:     name.column,
+			Token:      name.text,
+			Message:    fmt.Sprintf("invalid field name %q", name.text),
+			Suggestion: "use letters, digits and underscores, starting with a letter",
+		}
+	}
+	if err := checkReservedFieldName(name.text); err != nil {
+		return TemplateField{}, &FieldError{
+			Column:     name.column,
+			Token:      name.text,
+			Message:    err.Error(),
+			Suggestion: "every model has it already, choose another name",
+		}
+	}
+
+	if len(tokens) < 2 || tokens[1].text == "" {
+		column := len(arg) + 1
+		if len(tokens) >= 2 {
+			column = tokens[1].column
+		}
+		return TemplateField{}, &FieldError{
+			Column:     column,
+			Message:    "missing field type",
+			Suggestion: fmt.Sprintf("did you mean %s:string?", name.text),
+		}
+	}
+
+	typeToken := tokens[1]
+	goType := typeToken.text
+	modifiers := tokens[2:]
+	if i := strings.IndexByte(goType, '='); i >= 0 {
+		// type=VALUE is shorthand for :default=VALUE
+		def := fieldToken{text: "default" + goType[i:], column: typeToken.column + i - len("default")}
+		modifiers = append([]fieldToken{def}, modifiers...)
+		goType = goType[:i]
+		typeToken.text = goType
+	}
+	nullable := false
+	if strings.HasSuffix(goType, "?") {
+		nullable = true
+		goType = strings.TrimSuffix(goType, "?")
+	}
+	var enumValues []string
+	if open := strings.IndexByte(goType, '('); open >= 0 && goType[:open] == "enum" {
+		enumValues, err = parseEnumValues(goType[open:], typeToken.column+open)
+		if err != nil {
+			return TemplateField{}, err
+		}
+		goType = "enum"
+	} else if goType == "enum" {
+		return TemplateField{}, &FieldError{
+			Column:     typeToken.column,
+			Token:      goType,
+			Message:    "enum needs a list of values",
+			Suggestion: "e.g. enum(draft,published,archived)",
+		}
+	}
+	precision, scale, currency := 10, 2, ""
+	if open := strings.IndexByte(goType, '('); open >= 0 && containsString(decimalTypes, goType[:open]) {
+		precision, scale, currency, err = parseDecimalArgs(goType[:open], goType[open:], typeToken.column+open)
+		if err != nil {
+			return TemplateField{}, err
+		}
+		goType = goType[:open]
+	} else if goType == "money" {
+		precision, scale, currency = moneyPrecision, 2, "USD"
+	}
+	var slugSource string
+	if open := strings.IndexByte(goType, '('); open >= 0 && goType[:open] == "slug" {
+		slugSource, err = parseSlugSource(goType[open:], typeToken.column+open)
+		if err != nil {
+			return TemplateField{}, err
+		}
+		goType = "slug"
+	}
+	var relationship, relatedModel string
+	if open := strings.IndexByte(goType, '('); open >= 0 && containsString(relationTypes, goType[:open]) {
+		relationship = goType[:open]
+		relatedModel, err = parseRelation(relationship, goType[open:], typeToken.column+open)
+		if err != nil {
+			return TemplateField{}, err
+		}
+		goType = relationship
+	} else if containsString(relationTypes, goType) {
+		relationship = goType
+	}
+	if !containsString(fieldTypes, goType) {
+		return TemplateField{}, &FieldError{
+			Column:     typeToken.column,
+			Token:      goType,
+			Message:    fmt.Sprintf("unknown type %q", goType),
+			Suggestion: suggestion(goType, fieldTypes),
+		}
+	}
+
+	field := newTemplateField(name.text, goType)
+	field.Nullable = nullable
+	if enumValues != nil {
+		field.setEnumValues(enumValues)
+	}
+	if relationship != "" {
+		field.setRelation(relationship, name.text, relatedModel)
+	} else if model, ok := detectRelation(name.text, goType); ok {
+		field.setRelation("belongs_to", name.text, model)
+	}
+	if containsString(attachmentTypes, goType) {
+		field.setAttachment(goType)
+	}
+	if containsString(dateTypes, goType) {
+		field.setDateType(goType)
+	}
+	if containsString(jsonTypes, goType) {
+		field.setJSONType(goType)
+	}
+	if containsString(decimalTypes, goType) {
+		field.setDecimalType(precision, scale, currency)
+	}
+	if goType == "slug" {
+		field.setSlug(slugSource)
+	}
+
+	seen := map[string]bool{}
+	explicitRequired := false
+	for _, tok := range modifiers {
+		key, value, hasValue := strings.Cut(tok.text, "=")
+		if key == "" {
+			return TemplateField{}, &FieldError{
+				Column:     tok.column,
+				Token:      tok.text,
+				Message:    "empty modifier",
+				Suggestion: "remove the extra ':'",
+			}
+		}
+		if !containsString(fieldModifiers, key) {
+			return TemplateField{}, &FieldError{
+				Column:     tok.column,
+				Token:      key,
+				Message:    fmt.Sprintf("unknown modifier %q", key),
+				Suggestion: suggestion(key, fieldModifiers),
+			}
+		}
+		if seen[key] {
+			return TemplateField{}, &FieldError{
+				Column:     tok.column,
+				Token:      tok.text,
+				Message:    fmt.Sprintf("duplicate modifier %q", key),
+				Suggestion: "remove one of them",
+			}
+		}
+		seen[key] = true
+
+		if field.IsSlug {
+			return TemplateField{}, &FieldError{
+				Column:     tok.column,
+				Token:      tok.text,
+				Message:    fmt.Sprintf("modifier %q does not apply to slug fields", key),
+				Suggestion: "slugs are always unique and derived from their source",
+			}
+		}
+		if field.IsAttachment && key != "max" {
+			return TemplateField{}, &FieldError{
+				Column:     tok.column,
+				Token:      tok.text,
+				Message:    fmt.Sprintf("modifier %q does not apply to %s fields", key, field.Type),
+				Suggestion: "attachments take only max=N, their size limit in MB",
+			}
+		}
+
+		switch key {
+		case "required", "unique", "index":
+			if hasValue {
+				return TemplateField{}, &FieldError{
+					Column:     tok.column,
+					Token:      tok.text,
+					Message:    fmt.Sprintf("modifier %q does not take a value", key),
+					Suggestion: fmt.Sprintf("use :%s", key),
+				}
+			}
+			switch key {
+			case "required":
+				explicitRequired = true
+			case "unique":
+				field.Unique = true
+			case "index":
+				field.Index = true
+			}
+		case "min", "max":
+			if field.IsEnum || field.IsDate || field.IsDecimal || field.Type == "json" || field.Relationship != "" {
+				return TemplateField{}, &FieldError{
+					Column:     tok.column,
+					Token:      tok.text,
+					Message:    fmt.Sprintf("modifier %q does not apply to %s fields", key, field.Type),
+					Suggestion: "remove it",
+				}
+			}
+			n, err := strconv.Atoi(value)
+			if !hasValue || err != nil || n < 0 {
+				return TemplateField{}, &FieldError{
+					Column:     tok.column,
+					Token:      tok.text,
+					Message:    fmt.Sprintf("modifier %q needs a non-negative integer", key),
+					Suggestion: fmt.Sprintf("e.g. %s=255", key),
+				}
+			}
+			if field.IsAttachment {
+				if n == 0 {
+					return TemplateField{}, &FieldError{
+						Column:     tok.column,
+						Token:      tok.text,
+						Message:    "size limit must be at least 1 MB",
+						Suggestion: "e.g. max=5",
+					}
+				}
+				field.MaxSize = n
+			} else if key == "min" {
+				field.Min = value
+			} else {
+				field.Max = value
+			}
+		case "default":
+			if field.Relationship != "" || field.IsJSON {
+				return TemplateField{}, &FieldError{
+					Column:     tok.column,
+					Token:      tok.text,
+					Message:    fmt.Sprintf("modifier \"default\" does not apply to %s fields", field.Type),
+					Suggestion: "remove it",
+				}
+			}
+			if !hasValue {
+				return TemplateField{}, &FieldError{
+					Column:     tok.column,
+					Token:      tok.text,
+					Message:    "modifier \"default\" needs a value",
+					Suggestion: "e.g. default=\"\" or default=0",
+				}
+			}
+			def, err := parseDefaultValue(field, unquote(value))
+			if err != nil {
+				fe := &FieldError{
+					Column:     tok.column + len("default="),
+					Token:      value,
+					Message:    err.Error(),
+					Suggestion: fmt.Sprintf("use a %s literal", goType),
+				}
+				if field.IsEnum {
+					fe.Suggestion = "expected one of: " + strings.Join(field.enumValueList(), ", ")
+				} else if field.IsDate {
+					fe.Suggestion = "e.g. " + dateFormats[goType].example
+				} else if field.IsDecimal {
+					fe.Suggestion = "e.g. default=" + field.DecimalPlaceholder()
+				} else if field.TypeScriptType == "string" {
+					fe.Suggestion = "leave out \", `, \\, ; and line breaks"
+				} else if field.TypeScriptType == "boolean" {
+					fe.Suggestion = "use true or false"
+				} else if containsString([]string{"float", "float64"}, goType) {
+					fe.Suggestion = "e.g. default=1.5"
+				} else if field.TypeScriptType == "number" {
+					fe.Suggestion = "e.g. default=10"
+				}
+				return TemplateField{}, fe
+			}
+			field.Default = def
+			field.HasDefault = true
+			field.InitialValue = tsLiteral(field.TypeScriptType, def)
+		}
+	}
+
+	if nullable && field.IsSlug {
+		return TemplateField{}, &FieldError{
+			Column:     typeToken.column,
+			Token:      typeToken.text,
+			Message:    "slug fields cannot be nullable",
+			Suggestion: "drop the ?",
+		}
+	}
+	if explicitRequired && nullable {
+		return TemplateField{}, &FieldError{
+			Column:     typeToken.column,
+			Token:      typeToken.text,
+			Message:    "field is marked both nullable (?) and required",
+			Suggestion: "drop the ? or the :required modifier",
+		}
+	}
+	if field.Min != "" && field.Max != "" {
+		lo, _ := strconv.Atoi(field.Min)
+		hi, _ := strconv.Atoi(field.Max)
+		if lo > hi {
+			return TemplateField{}, &FieldError{
+				Column:     len(arg) + 1,
+				Message:    fmt.Sprintf("min=%d is greater than max=%d", lo, hi),
+				Suggestion: "swap the bounds",
+			}
+		}
+	}
+
+	// Strings, emails and enums are required in generated forms; "?" or a
+	// default opts out, and "required" opts in for every other type.
+	field.Required = explicitRequired || (!nullable && !field.HasDefault && (goType == "string" || goType == "email" || goType == "enum"))
+
+	if nullable && field.Relationship == "" && !field.IsAttachment && !field.IsJSON {
+		field.setNullable()
+	}
+
+	field.GORMTag = buildGORMTag(field)
+	field.BindingTag = buildBindingTag(field)
+	field.ZodSchema = buildZodSchema(field)
+
+	return field, nil
+}
+
+// parseEnumValues parses the "(a,b,c)" list of an enum type starting at
+// column
+func parseEnumValues(list string, column int) ([]string, error) {
+	if !strings.HasSuffix(list, ")") {
+		return nil, &FieldError{
+			Column:     column,
+			Token:      list,
+			Message:    "unterminated enum value list",
+			Suggestion: "add a closing )",
+		}
+	}
+
+	var values []string
+	offset := column + 1
+	for _, value := range strings.Split(list[1:len(list)-1], ",") {
+		trimmed := strings.TrimSpace(value)
+		switch {
+		case trimmed == "":
+			return nil, &FieldError{
+				Column:     offset,
+				Message:    "empty enum value",
+				Suggestion: "e.g. enum(draft,published,archived)",
+			}
+		case !enumValuePattern.MatchString(trimmed):
+			return nil, &FieldError{
+				Column:     offset,
+				Token:      value,
+				Message:    fmt.Sprintf("invalid enum value %q", trimmed),
+				Suggestion: "use letters, digits, underscores and dashes",
+			}
+		case containsString(values, trimmed):
+			return nil, &FieldError{
+				Column:     offset,
+				Token:      value,
+				Message:    fmt.Sprintf("duplicate enum value %q", trimmed),
+				Suggestion: "remove one of them",
+			}
+		}
+		values = append(values, trimmed)
+		offset += len(value) + 1
+	}
+	return values, nil
+}
+
+// setEnumValues makes the field an enum of the given values, typed in
+// TypeScript as a union of string literals
+func (f *TemplateField) setEnumValues(values []string) {
+	f.IsEnum = true
+	f.EnumValues = nil
+	for i, v := range values {
+		f.EnumValues = append(f.EnumValues, EnumValue{Value: v, Label: humanize(v), Color: enumColor(v, i)})
+	}
+	f.TypeScriptType = strings.Join(f.enumLiterals(), " | ")
+	f.ZeroValue = "undefined"
+	f.InitialValue = "undefined"
+}
+
+// enumValueList returns the values of an enum field
+func (f TemplateField) enumValueList() []string {
+	var values []string
+	for _, v := range f.EnumValues {
+		values = append(values, v.Value)
+	}
+	return values
+}
+
+// enumLiterals returns the values of an enum field as TypeScript literals
+func (f TemplateField) enumLiterals() []string {
+	var literals []string
+	for _, v := range f.EnumValues {
+		literals = append(literals, tsLiteral("string", v.Value))
+	}
+	return literals
+}
+
+// enumColors are the badge colors of common enum values
+var enumColors = map[string]string{
+	"active": "green", "approved": "green", "completed": "green", "done": "green",
+	"enabled": "green", "paid": "green", "published": "green", "success": "green",
+	"pending": "yellow", "processing": "yellow", "review": "yellow", "in_review": "yellow", "waiting": "yellow",
+	"blocked": "red", "canceled": "red", "cancelled": "red", "error": "red", "failed": "red", "rejected": "red",
+	"archived": "gray", "closed": "gray", "disabled": "gray", "draft": "gray", "inactive": "gray",
+}
+
+// enumPalette colors the other values of an enum in turn
+var enumPalette = []string{"blue", "purple", "orange", "teal", "pink"}
+
+// enumColor picks the badge color of the i-th value of an enum
+func enumColor(value string, i int) string {
+	if color, ok := enumColors[strings.ToLower(strings.ReplaceAll(value, "-", "_"))]; ok {
+		return color
+	}
+	return enumPalette[i%len(enumPalette)]
+}
+
+// parseDefaultValue checks that a default value fits the field type
+func parseDefaultValue(f TemplateField, value string) (string, error) {
+	if f.IsEnum {
+		for _, v := range f.EnumValues {
+			if v.Value == value {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("default %q is not one of the enum values", value)
+	}
+	if f.IsDate {
+		return value, checkDateDefault(f.Type, value)
+	}
+	if f.IsDecimal {
+		return value, checkDecimalDefault(f, value)
+	}
+
+	// Numbers and booleans are normalised, as they are written unquoted to
+	// Go, SQL and TypeScript
+	switch f.Type {
+	case "int", "int64":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("default %q is not an integer", value)
+		}
+		return strconv.FormatInt(n, 10), nil
+	case "uint", "uint64":
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("default %q is not a non-negative integer", value)
+		}
+		return strconv.FormatUint(n, 10), nil
+	case "float", "float64":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return "", fmt.Errorf("default %q is not a finite number", value)
+		}
+		return strconv.FormatFloat(n, 'g', -1, 64), nil
+	case "bool", "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("default %q is not a boolean", value)
+		}
+		return strconv.FormatBool(b), nil
+	}
+
+	if i := strings.IndexFunc(value, unsafeDefaultRune); i >= 0 {
+		return "", fmt.Errorf("default %q cannot contain %q", value, []rune(value[i:])[0])
+	}
+	return value, nil
+}
+
+// unsafeDefaultRune reports whether a text default cannot hold the rune: a
+// quote, backquote or backslash ends or escapes the struct tag it is written
+// to, a semicolon splits the gorm tag, and control characters such as line
+// breaks have no place in either
+func unsafeDefaultRune(r rune) bool {
+	return strings.ContainsRune("\"`\\;", r) || unicode.IsControl(r)
+}
+
+// buildGORMTag derives the gorm struct tag from the field modifiers
+func buildGORMTag(f TemplateField) string {
+	if f.Relationship == "many_to_many" || f.IsAttachment {
+		return ""
+	}
+
+	var parts []string
+	if f.IsJSON {
+		parts = append(parts, "serializer:json")
+	} else if f.IsDecimal {
+		parts = append(parts, fmt.Sprintf("type:decimal(%d,%d)", f.Precision, f.Scale))
+	} else if f.IsSlug {
+		parts = append(parts, "size:"+slugSize)
+	} else if f.Type == "text" || f.Type == "date" || f.Type == "time" {
+		parts = append(parts, "type:"+f.Type)
+	} else if f.Max != "" && f.TypeScriptType == "string" {
+		parts = append(parts, "size:"+f.Max)
+	}
+	if f.Required && !f.IsBool || f.HasDefault && !f.IsNullable {
+		parts = append(parts, "not null")
+	}
+	if f.Unique {
+		parts = append(parts, "uniqueIndex")
+	} else if f.Index {
+		parts = append(parts, "index")
+	}
+	if f.HasDefault {
+		if f.TypeScriptType != "number" && f.TypeScriptType != "boolean" {
+			parts = append(parts, "default:'"+strings.ReplaceAll(f.Default, "'", "''")+"'")
+		} else {
+			parts = append(parts, "default:"+f.Default)
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// updateBindingTag derives the validator rules for update requests from
+// those for create requests: every field is optional, and enums are checked
+// by the validator with their IsValid method
+func updateBindingTag(create string) string {
+	var rules []string
+	all := strings.Split(create, ",")
+	for i, rule := range all {
+		if rule == "dive" {
+			rules = append(rules, all[i:]...)
+			break
+		}
+		if rule != "" && rule != "required" && rule != "omitempty" && !strings.HasPrefix(rule, "oneof=") {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return ""
+	}
+	return strings.Join(append([]string{"omitempty"}, rules...), ",")
+}
+
+// buildBindingTag derives the validator rules for create requests
+func buildBindingTag(f TemplateField) string {
+	if f.IsAttachment {
+		return ""
+	}
+
+	var rules []string
+	if f.Required && !f.IsBool {
+		rules = append(rules, "required")
+	}
+	switch f.Type {
+	case "email":
+		rules = append(rules, "email")
+	case "url":
+		rules = append(rules, "url")
+	case "time":
+		rules = append(rules, "datetime=15:04")
+	case "enum":
+		rules = append(rules, "oneof="+strings.Join(f.enumValueList(), " "))
+	case "many_to_many":
+		if f.Required {
+			rules = append(rules, "min=1")
+		}
+	case "[]string", "[]int":
+		if f.Required && f.Min == "" {
+			rules = append(rules, "min=1")
+		}
+	}
+	if f.Min != "" {
+		rules = append(rules, "min="+f.Min)
+	}
+	if f.Max != "" {
+		rules = append(rules, "max="+f.Max)
+	}
+	if f.Type == "[]string" {
+		rules = append(rules, "dive", "required")
+	}
+	if len(rules) > 0 && !f.Required {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return strings.Join(rules, ",")
+}
+
+// buildZodSchema derives the zod validator used by the generated form
+func buildZodSchema(f TemplateField) string {
+	if f.IsAttachment {
+		return attachmentZodSchema(f)
+	}
+
+	var b strings.Builder
+
+	switch {
+	case f.IsJSON:
+		b.WriteString(jsonZodSchema(f))
+	case f.TypeScriptType == "number":
+		b.WriteString("z.number()")
+		if f.Type != "float" && f.Type != "float64" {
+			b.WriteString(".int()")
+		}
+		if f.Type == "uint" || f.Type == "uint64" {
+			b.WriteString(".nonnegative()")
+		}
+		if f.Min != "" {
+			b.WriteString(".min(" + f.Min + ")")
+		}
+		if f.Max != "" {
+			b.WriteString(".max(" + f.Max + ")")
+		}
+	case f.TypeScriptType == "number[]":
+		b.WriteString("z.array(z.number().int())")
+		if f.Required {
+			fmt.Fprintf(&b, ".min(1, 'Select at least one %s')", strings.ToLower(humanize(singularize(f.RelatedName))))
+		}
+	case f.TypeScriptType == "boolean":
+		b.WriteString("z.boolean()")
+	default:
+		if f.IsEnum {
+			b.WriteString("z.enum([" + strings.Join(f.enumLiterals(), ", ") + "])")
+			break
+		}
+		if f.IsDate {
+			b.WriteString(dateZodSchema(f))
+			break
+		}
+		if f.IsDecimal {
+			b.WriteString(decimalZodSchema(f))
+			break
+		}
+		b.WriteString("z.string()")
+		switch f.Type {
+		case "email":
+			b.WriteString(".email('Invalid email address')")
+		case "url":
+			b.WriteString(".url('Invalid URL')")
+		}
+		if f.Min != "" {
+			fmt.Fprintf(&b, ".min(%s, '%s must be at least %s characters')", f.Min, f.Label, f.Min)
+		} else if f.Required {
+			fmt.Fprintf(&b, ".min(1, '%s is required')", f.Label)
+		}
+		if f.Max != "" {
+			fmt.Fprintf(&b, ".max(%s, '%s must be at most %s characters')", f.Max, f.Label, f.Max)
+		}
+	}
+
+	if f.IsNullable {
+		b.WriteString(".nullable()")
+	}
+	if f.HasDefault {
+		b.WriteString(".default(" + tsLiteral(f.TypeScriptType, f.Default) + ")")
+	} else if !f.Required && (f.Nullable || f.TypeScriptType != "number" || f.Relationship != "") {
+		b.WriteString(".optional()")
+	}
+
+	return b.String()
+}
+
+// tsLiteral renders a default value as a TypeScript literal
+func tsLiteral(tsType, value string) string {
+	if tsType != "number" && tsType != "boolean" {
+		return "'" + tsStringEscaper.Replace(value) + "'"
+	}
+	return value
+}
+
+// tsStringEscaper escapes text for a single-quoted TypeScript string
+var tsStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "\u2028", `\u2028`, "\u2029", `\u2029`)
+
+// unquote strips matching single or double quotes around a value
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// suggestion returns a "did you mean" hint for the closest candidate
+func suggestion(word string, candidates []string) string {
+	best := ""
+	bestDist := len(word)/2 + 2
+	for _, c := range candidates {
+		if d := levenshtein(strings.ToLower(word), c); d < bestDist {
+			best = c
+			bestDist = d
+		}
+	}
+	if best == "" {
+		return "expected one of: " + strings.Join(candidates, ", ")
+	}
+	return fmt.Sprintf("did you mean %q?", best)
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package construct
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		arg      string
+		name     string
+		typ      string
+		required bool
+		unique   bool
+		index    bool
+		nullable bool
+		min, max string
+		def      string // Default, when hasDefault
+		hasDef   bool
+		gorm     string
+		binding  string
+	}{
+		{arg: "title:string", name: "title", typ: "string", required: true, gorm: "not null", binding: "required"},
+		{arg: "title:string:required:unique:max=255", name: "title", typ: "string", required: true, unique: true, max: "255", gorm: "size:255;not null;uniqueIndex", binding: "required,max=255"},
+		{arg: "status:string:index", name: "status", typ: "string", required: true, index: true, gorm: "not null;index", binding: "required"},
+		{arg: "name:string:min=2:max=50", name: "name", typ: "string", required: true, min: "2", max: "50", gorm: "size:50;not null", binding: "required,min=2,max=50"},
+		{arg: `bio:text?:default=""`, name: "bio", typ: "text", nullable: true, def: "", hasDef: true, gorm: "type:text;default:''"},
+		{arg: "email:email:required", name: "email", typ: "email", required: true, gorm: "not null", binding: "required,email"},
+		{arg: "website:url", name: "website", typ: "url", binding: "omitempty,url"},
+		{arg: "views:uint:min=1", name: "views", typ: "uint", min: "1", binding: "omitempty,min=1"},
+		{arg: "score:float?", name: "score", typ: "float", nullable: true},
+		{arg: "rank:int=10", name: "rank", typ: "int", def: "10", hasDef: true, gorm: "not null;default:10"},
+		{arg: "rank:int=+07", name: "rank", typ: "int", def: "7", hasDef: true, gorm: "not null;default:7"},
+		{arg: "count:uint64:default=3", name: "count", typ: "uint64", def: "3", hasDef: true, gorm: "not null;default:3"},
+		{arg: "price:float=1.50", name: "price", typ: "float", def: "1.5", hasDef: true, gorm: "not null;default:1.5"},
+		{arg: "active:bool=t", name: "active", typ: "bool", def: "true", hasDef: true, gorm: "not null;default:true"},
+		{arg: "active:bool:default=0", name: "active", typ: "bool", def: "false", hasDef: true, gorm: "not null;default:false"},
+		{arg: "note:string='it is'", name: "note", typ: "string", def: "it is", hasDef: true, gorm: "not null;default:'it is'"},
+		{arg: `note:string="don't"`, name: "note", typ: "string", def: "don't", hasDef: true, gorm: "not null;default:'don''t'"},
+		{arg: "opens_at:time:default='09:00'", name: "opens_at", typ: "time", def: "09:00", hasDef: true, gorm: "type:time;not null;default:'09:00'", binding: "omitempty,datetime=15:04"},
+		{arg: "status:enum(draft,published):default=draft", name: "status", typ: "enum", def: "draft", hasDef: true, gorm: "not null;default:'draft'", binding: "omitempty,oneof=draft published"},
+		{arg: "category:belongs_to:required", name: "category_id", typ: "belongs_to", required: true, index: true, gorm: "not null;index", binding: "required"},
+		{arg: "author:belongs_to(User)", name: "author_id", typ: "belongs_to", index: true, gorm: "index"},
+		{arg: "tags:many_to_many", name: "tag_ids", typ: "many_to_many"},
+		{arg: "tags:[]string:max=10", name: "tags", typ: "[]string", max: "10", gorm: "serializer:json", binding: "omitempty,max=10,dive,required"},
+		{arg: "starts_on:date:required", name: "starts_on", typ: "date", required: true, gorm: "type:date;not null", binding: "required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			f, err := parseField(tt.arg)
+			if err != nil {
+				t.Fatalf("parseField(%q) failed: %v", tt.arg, err)
+			}
+			if f.Name != tt.name || f.Type != tt.typ {
+				t.Errorf("parsed %s:%s, want %s:%s", f.Name, f.Type, tt.name, tt.typ)
+			}
+			if f.Required != tt.required || f.Unique != tt.unique || f.Index != tt.index || f.IsNullable != tt.nullable {
+				t.Errorf("required=%v unique=%v index=%v nullable=%v, want %v %v %v %v", f.Required, f.Unique, f.Index, f.IsNullable, tt.required, tt.unique, tt.index, tt.nullable)
+			}
+			if f.Min != tt.min || f.Max != tt.max {
+				t.Errorf("min=%q max=%q, want %q %q", f.Min, f.Max, tt.min, tt.max)
+			}
+			if f.HasDefault != tt.hasDef || f.Default != tt.def {
+				t.Errorf("default %v %q, want %v %q", f.HasDefault, f.Default, tt.hasDef, tt.def)
+			}
+			if got := buildGORMTag(f); got != tt.gorm {
+				t.Errorf("buildGORMTag() = %q, want %q", got, tt.gorm)
+			}
+			if got := buildBindingTag(f); got != tt.binding {
+				t.Errorf("buildBindingTag() = %q, want %q", got, tt.binding)
+			}
+		})
+	}
+}
+
+func TestParseFieldErrors(t *testing.T) {
+	tests := []struct {
+		arg        string
+		column     int
+		token      string
+		message    string
+		suggestion string
+	}{
+		{"title", 6, "", "missing field type", "did you mean title:string?"},
+		{":string", 1, "", "missing field name", "expected name:type, e.g. title:string"},
+		{"9x:string", 1, "9x", `invalid field name "9x"`, "use letters, digits and underscores, starting with a letter"},
+		{"id:int", 1, "id", `field name "id" is a built-in column`, "every model has it already, choose another name"},
+		{"created_at:date", 1, "created_at", `field name "created_at" is a built-in column`, "every model has it already, choose another name"},
+		{"updated_at:time", 1, "updated_at", `field name "updated_at" is a built-in column`, "every model has it already, choose another name"},
+		{"deleted_at:time?", 1, "deleted_at", `field name "deleted_at" is a built-in column`, "every model has it already, choose another name"},
+		{"CreatedAt:time", 1, "CreatedAt", `field name "CreatedAt" is a built-in column`, "every model has it already, choose another name"},
+		{"title:strng", 7, "strng", `unknown type "strng"`, `did you mean "string"?`},
+		{"title:string:requird", 14, "requird", `unknown modifier "requird"`, `did you mean "required"?`},
+		{"title:string:", 14, "", "empty modifier", "remove the extra ':'"},
+		{"title:string:required:required", 23, "required", `duplicate modifier "required"`, "remove one of them"},
+		{"title:string:max=abc", 14, "max=abc", `modifier "max" needs a non-negative integer`, "e.g. max=255"},
+		{"status:enum(draft", 12, "(draft", "unterminated enum value list", "add a closing )"},
+		{"status:enum(draft):default=x", 28, "x", `default "x" is not one of the enum values`, "expected one of: draft"},
+		{"tags:many_to_many:default=1", 19, "default=1", `modifier "default" does not apply to many_to_many fields`, "remove it"},
+		{"rank:int=1.5", 10, "1.5", `default "1.5" is not an integer`, "e.g. default=10"},
+		{"rank:uint=-1", 11, "-1", `default "-1" is not a non-negative integer`, "e.g. default=10"},
+		{"score:float=Inf", 13, "Inf", `default "Inf" is not a finite number`, "e.g. default=1.5"},
+		{"score:float=NaN", 13, "NaN", `default "NaN" is not a finite number`, "e.g. default=1.5"},
+		{"active:bool=yes", 13, "yes", `default "yes" is not a boolean`, "use true or false"},
+		{`note:string=a"b`, 14, `"b`, "unterminated quoted value", `add a closing "`},
+		{`note:string='say "hi"'`, 13, `'say "hi"'`, `default "say \"hi\"" cannot contain '"'`, "leave out \", `, \\, ; and line breaks"},
+		{"note:string=a;b", 13, "a;b", `default "a;b" cannot contain ';'`, "leave out \", `, \\, ; and line breaks"},
+		{`note:string=a\b`, 13, `a\b`, `default "a\\b" cannot contain '\\'`, "leave out \", `, \\, ; and line breaks"},
+		{"note:string=a`b", 13, "a`b", "default \"a`b\" cannot contain '`'", "leave out \", `, \\, ; and line breaks"},
+		{"note:string='a\nb'", 13, "'a\nb'", `default "a\nb" cannot contain '\n'`, "leave out \", `, \\, ; and line breaks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			_, err := parseFieldsToTemplateFields([]string{"name:string", tt.arg})
+			fe, ok := err.(*FieldError)
+			if !ok {
+				t.Fatalf("parseFieldsToTemplateFields(%q) = %v, want a FieldError", tt.arg, err)
+			}
+			if fe.Arg != tt.arg || fe.Index != 2 {
+				t.Errorf("error for field #%d %q, want #2 %q", fe.Index, fe.Arg, tt.arg)
+			}
+			if fe.Column != tt.column || fe.Token != tt.token {
+				t.Errorf("error at column %d on %q, want %d on %q", fe.Column, fe.Token, tt.column, tt.token)
+			}
+			if fe.Message != tt.message || fe.Suggestion != tt.suggestion {
+				t.Errorf("error %q (%q), want %q (%q)", fe.Message, fe.Suggestion, tt.message, tt.suggestion)
+			}
+		})
+	}
+}
+
+func TestDuplicateField(t *testing.T) {
+	_, err := parseFieldsToTemplateFields([]string{"title:string", "body:text", "title:text"})
+	fe, ok := err.(*FieldError)
+	if !ok || fe.Index != 3 || fe.Message != `duplicate field "title"` || fe.Suggestion != "already defined by field #1" {
+		t.Errorf("parseFieldsToTemplateFields() = %v, want a duplicate field error on field #3", err)
+	}
+}
+
+func TestReservedFieldTargets(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range reservedFieldNames {
+		if err := AddFields(root, "Post", []string{name + ":string"}, GenerateOptions{}); err == nil || !strings.Contains(err.Error(), "is a built-in column") {
+			t.Errorf("AddFields(%s) = %v, want a built-in column error", name, err)
+		}
+		if err := RenameField(root, "Post", "title", name, GenerateOptions{}); err == nil || !strings.Contains(err.Error(), "is a built-in column") {
+			t.Errorf("RenameField(title, %s) = %v, want a built-in column error", name, err)
+		}
+	}
+}
+
+func TestFieldErrorMessage(t *testing.T) {
+	_, err := parseFieldsToTemplateFields([]string{"title:strng"})
+	want := "field #1 \"title:strng\": unknown type \"strng\" (did you mean \"string\"?)\n      title:strng\n            ^^^^^"
+	if err == nil || err.Error() != want {
+		t.Errorf("error =\n%v\nwant\n%s", err, want)
+	}
+}
+
+func TestBuildZodSchema(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"title:string:required:max=255", "z.string().min(1, 'Title is required').max(255, 'Title must be at most 255 characters')"},
+		{"name:string:min=2:max=50", "z.string().min(2, 'Name must be at least 2 characters').max(50, 'Name must be at most 50 characters')"},
+		{`bio:text?:default=""`, "z.string().nullable().default('')"},
+		{"email:email:required", "z.string().email('Invalid email address').min(1, 'Email is required')"},
+		{"website:url", "z.string().url('Invalid URL').optional()"},
+		{"rank:int=10", "z.number().int().default(10)"},
+		{"views:uint:min=1", "z.number().int().nonnegative().min(1)"},
+		{"price:float=1.50", "z.number().default(1.5)"},
+		{"score:float?", "z.number().nullable().optional()"},
+		{"active:bool=t", "z.boolean().default(true)"},
+		{"note:string='it is'", "z.string().default('it is')"},
+		{`note:string="don't"`, `z.string().default('don\'t')`},
+		{"status:enum(draft,published):default=draft", "z.enum(['draft', 'published']).default('draft')"},
+		{"tags:many_to_many", "z.array(z.number().int()).optional()"},
+		{"tags:[]string:max=10", "z.array(z.string().trim().min(1, 'Tags cannot be blank')).max(10, 'At most 10 tags').optional()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			f, err := parseField(tt.arg)
+			if err != nil {
+				t.Fatalf("parseField(%q) failed: %v", tt.arg, err)
+			}
+			if got := buildZodSchema(f); got != tt.want {
+				t.Errorf("buildZodSchema() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTSLiteral(t *testing.T) {
+	tests := []struct {
+		tsType, value, want string
+	}{
+		{"string", "draft", "'draft'"},
+		{"string", "don't", `'don\'t'`},
+		{"string", `C:\temp`, `'C:\\temp'`},
+		{"string", "a\nb\tc", `'a\nb\tc'`},
+		{"number", "10", "10"},
+		{"boolean", "true", "true"},
+	}
+	for _, tt := range tests {
+		if got := tsLiteral(tt.tsType, tt.value); got != tt.want {
+			t.Errorf("tsLiteral(%q, %q) = %s, want %s", tt.tsType, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateBindingTag(t *testing.T) {
+	tests := []struct {
+		create, want string
+	}{
+		{"", ""},
+		{"required", ""},
+		{"required,max=255", "omitempty,max=255"},
+		{"required,email", "omitempty,email"},
+		{"omitempty,url", "omitempty,url"},
+		{"omitempty,oneof=draft published", ""},
+		{"omitempty,max=10,dive,required", "omitempty,max=10,dive,required"},
+	}
+	for _, tt := range tests {
+		if got := updateBindingTag(tt.create); got != tt.want {
+			t.Errorf("updateBindingTag(%q) = %q, want %q", tt.create, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package construct
+
+import "strings"
+
+// The list endpoint filters the records by query parameters named after
+// their fields:
+//
+//	title=Hello                  text fields equal to a value
+//	title_contains=hello         text fields containing it, ignoring case
+//	status=draft                 enums, numbers, booleans and belongs_to
+//	status_in=draft,published    enums and belongs_to, one of the values
+//	views_gt=10, views_lt=100    numbers greater or less than a value
+//	published_on_from=2024-05-01 dates in a range, see dates.go
+//	q=hello                      any text field containing it
+//
+// sort and order sort by the fields that are Sortable. The generated store
+// keeps the parameters in the URL query string and shows the filters as
+// chips on the index page.
+
+// filterSuffixes are the suffixes of the parameters filtering a field,
+// besides the one named after it
+var filterSuffixes = []string{"_contains", "_gt", "_lt", "_in", "_from", "_to"}
+
+// Filter returns how the list endpoint filters the field: text, enum,
+// number, bool, relation or date, or "" when it does not
+func (f TemplateField) Filter() string {
+	switch {
+	case f.IsEnum:
+		return "enum"
+	case f.Relationship == "belongs_to":
+		return "relation"
+	case f.Relationship != "":
+		return ""
+	case f.IsSlug || containsString([]string{"string", "text", "email", "url"}, f.Type):
+		return "text"
+	case containsString([]string{"int", "uint", "int64", "uint64", "float", "float64"}, f.Type):
+		return "number"
+	case f.IsBool:
+		return "bool"
+	case f.Type == "date" || f.Type == "datetime":
+		return "date"
+	}
+	return ""
+}
+
+// FilterParam is a query parameter of the list endpoint filtering a field
+type FilterParam struct {
+	Name           string // e.g. title_contains
+	Label          string // Label of its filter chip, e.g. Title contains
+	TypeScriptType string
+	Value          string // Type the store reads it as from the URL: string, number, boolean, string[] or number[]
+}
+
+// FilterParams returns the query parameters filtering the field
+func (f TemplateField) FilterParams() []FilterParam {
+	value := "string"
+	switch f.TypeScriptType {
+	case "number", "boolean":
+		value = f.TypeScriptType
+	}
+	eq := FilterParam{Name: f.Name, Label: f.Label, TypeScriptType: f.TypeScriptType, Value: value}
+	in := FilterParam{Name: f.Name + "_in", Label: f.Label, TypeScriptType: "(" + f.TypeScriptType + ")[]", Value: value + "[]"}
+	switch f.Filter() {
+	case "text":
+		return []FilterParam{eq, {Name: f.Name + "_contains", Label: f.Label + " contains", TypeScriptType: "string", Value: "string"}}
+	case "enum", "relation":
+		if f.TypeScriptType == "number" {
+			in.TypeScriptType = "number[]"
+		}
+		return []FilterParam{eq, in}
+	case "number":
+		return []FilterParam{
+			eq,
+			{Name: f.Name + "_gt", Label: f.Label + " greater than", TypeScriptType: "number", Value: "number"},
+			{Name: f.Name + "_lt", Label: f.Label + " less than", TypeScriptType: "number", Value: "number"},
+		}
+	case "bool":
+		return []FilterParam{eq}
+	case "date":
+		return []FilterParam{
+			{Name: f.Name + "_from", Label: f.Label + " from", TypeScriptType: "string", Value: "string"},
+			{Name: f.Name + "_to", Label: f.Label + " to", TypeScriptType: "string", Value: "string"},
+		}
+	}
+	return nil
+}
+
+// filterGoNames returns the Go names of the filters of the field on the
+// list endpoint besides its own, such as TitleContains
+func (f TemplateField) filterGoNames() []string {
+	var suffixes []string
+	switch f.Filter() {
+	case "text":
+		suffixes = []string{"Contains"}
+	case "enum", "relation":
+		suffixes = []string{"In"}
+	case "number":
+		suffixes = []string{"Gt", "Lt"}
+	case "date":
+		suffixes = []string{"From", "To"}
+	}
+	names := make([]string, len(suffixes))
+	for i, suffix := range suffixes {
+		names[i] = f.FieldName + suffix
+	}
+	return names
+}
+
+// renameColumn renames a field in a string naming its column, alone or in
+// a condition such as "name >= ?" or "LOWER(name) LIKE ?", or one of its
+// filter parameters such as "name_contains"
+func renameColumn(value, oldName, newName string) (string, bool) {
+	if value == oldName || strings.HasPrefix(value, oldName+" ") {
+		return newName + value[len(oldName):], true
+	}
+	if rest, ok := strings.CutPrefix(value, "LOWER("+oldName+")"); ok {
+		return "LOWER(" + newName + ")" + rest, true
+	}
+	for _, suffix := range filterSuffixes {
+		if value == oldName+suffix {
+			return newName + suffix, true
+		}
+	}
+	return "", false
+}
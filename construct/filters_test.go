@@ -0,0 +1,145 @@
+package construct
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// renderedService renders the service.go of a resource with fields
+func renderedService(t *testing.T, fields ...string) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir()) // No user template overrides
+	data, err := NewTemplateData("Post", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	backendData := NewBackendTemplateData("example.com/app", data)
+	for _, tmpl := range backendTemplates(root, backendData) {
+		if tmpl.name != "base/service.tmpl" {
+			continue
+		}
+		file, err := renderFileTemplate(root, tmpl, backendData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(file.Content)
+	}
+	t.Fatal("no service.go template")
+	return ""
+}
+
+func TestLikePatternEscaping(t *testing.T) {
+	src := renderedService(t, "title:string", "body:text")
+	if n := strings.Count(src, "LIKE ?"); n == 0 || n != strings.Count(src, "LIKE ? ESCAPE '!'") {
+		t.Errorf("service.go has %d LIKE conditions, want each to use ESCAPE '!':\n%s", n, src)
+	}
+
+	if !strings.Contains(src, `return "%" + likeEscaper.Replace(strings.ToLower(text)) + "%"`) {
+		t.Errorf("likePattern does not wrap the escaped text in %%:\n%s", src)
+	}
+
+	// Rebuild likeEscaper from the replacements in the generated code
+	file, err := parser.ParseFile(token.NewFileSet(), "service.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pairs []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || spec.Names[0].Name != "likeEscaper" {
+			return true
+		}
+		for _, arg := range spec.Values[0].(*ast.CallExpr).Args {
+			s, err := strconv.Unquote(arg.(*ast.BasicLit).Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pairs = append(pairs, s)
+		}
+		return false
+	})
+	if len(pairs) == 0 {
+		t.Fatalf("service.go has no likeEscaper:\n%s", src)
+	}
+	escaper := strings.NewReplacer(pairs...)
+
+	for _, tt := range []struct {
+		text, pattern string
+	}{
+		{"hello", "%hello%"},
+		{"50%", "%50!%%"},
+		{"snake_case", "%snake!_case%"},
+		{"wow!", "%wow!!%"},
+		{`c:\dir`, `%c:\dir%`},
+		{"!%_", "%!!!%!_%"},
+	} {
+		if got := "%" + escaper.Replace(tt.text) + "%"; got != tt.pattern {
+			t.Errorf("likePattern(%q) = %q, want %q", tt.text, got, tt.pattern)
+		}
+	}
+}
+
+func TestFieldFilters(t *testing.T) {
+	tests := []struct {
+		arg     string
+		filter  string
+		params  []string // Name:Value of each query parameter
+		goNames []string
+	}{
+		{"title:string", "text", []string{"title:string", "title_contains:string"}, []string{"TitleContains"}},
+		{"website:url", "text", []string{"website:string", "website_contains:string"}, []string{"WebsiteContains"}},
+		{"slug:slug(title)", "text", []string{"slug:string", "slug_contains:string"}, []string{"SlugContains"}},
+		{"status:enum(draft,published)", "enum", []string{"status:string", "status_in:string[]"}, []string{"StatusIn"}},
+		{"author:belongs_to(User)", "relation", []string{"author_id:number", "author_id_in:number[]"}, []string{"AuthorIdIn"}},
+		{"views:int", "number", []string{"views:number", "views_gt:number", "views_lt:number"}, []string{"ViewsGt", "ViewsLt"}},
+		{"score:float?", "number", []string{"score:number", "score_gt:number", "score_lt:number"}, []string{"ScoreGt", "ScoreLt"}},
+		{"active:bool", "bool", []string{"active:boolean"}, []string{}},
+		{"published_on:date", "date", []string{"published_on_from:string", "published_on_to:string"}, []string{"PublishedOnFrom", "PublishedOnTo"}},
+		{"starts_at:datetime", "date", []string{"starts_at_from:string", "starts_at_to:string"}, []string{"StartsAtFrom", "StartsAtTo"}},
+		{"opens_at:time", "", nil, []string{}},
+		{"tags:many_to_many", "", nil, []string{}},
+		{"meta:json", "", nil, []string{}},
+		{"price:money", "", nil, []string{}},
+		{"avatar:image", "", nil, []string{}},
+	}
+	for _, tt := range tests {
+		f, err := parseField(tt.arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var params []string
+		for _, p := range f.FilterParams() {
+			params = append(params, p.Name+":"+p.Value)
+		}
+		if f.Filter() != tt.filter || !reflect.DeepEqual(params, tt.params) || !reflect.DeepEqual(f.filterGoNames(), tt.goNames) {
+			t.Errorf("%s filters as %q by %q (%q), want %q by %q (%q)", tt.arg, f.Filter(), params, f.filterGoNames(), tt.filter, tt.params, tt.goNames)
+		}
+	}
+}
+
+func TestRenameColumn(t *testing.T) {
+	tests := []struct {
+		value, renamed string
+		ok             bool
+	}{
+		{"summary", "excerpt", true},
+		{"summary >= ?", "excerpt >= ?", true},
+		{"LOWER(summary) LIKE ? ESCAPE '!'", "LOWER(excerpt) LIKE ? ESCAPE '!'", true},
+		{"summary_contains", "excerpt_contains", true},
+		{"summary_from", "excerpt_from", true},
+		{"summary_count", "summary_count", false},
+		{"summarys", "summarys", false},
+		{"title", "title", false},
+	}
+	for _, tt := range tests {
+		if got, ok := renameColumn(tt.value, "summary", "excerpt"); ok != tt.ok || ok && got != tt.renamed {
+			t.Errorf("renameColumn(%q) = %q, %v, want %q, %v", tt.value, got, ok, tt.renamed, tt.ok)
+		}
+	}
+}
@@ -16,78 +16,241 @@ var generateCmd = &mamba.Command{
 
 Creates:
   • Go: Model, Controller, Service, Routes
-  • Vue: Page, Store, Composables, Modals, Types
+  • Vue: Page, Store, API module, Modals, Types
   • Automatic API integration
 
 Examples:
   construct g Post title:string content:text published:bool
   construct g:b Product name:string price:float stock:uint
   construct g:f Category name:string description:text
+  construct g Author name:string:required:max=100 email:email:unique bio:text?
+  construct g Player name:string nickname:string? rank:int=10
+  construct g Post title:string "status:enum(draft,published,archived):default=draft"
+  construct g Post title:string category:belongs_to tags:many_to_many
+  construct g Author name:string avatar:image resume:file:max=20
+  construct g Event title:string starts_on:date:required starts_at:datetime
+  construct g --from construct.schema.yaml
+
+Fields:
+  name:type[?][=default][:modifier]...
+
+  A trailing ? makes the field nullable: a pointer in Go, "| null" in
+  TypeScript, and cleared by null in update requests. type=V is shorthand
+  for :default=V. Modifiers:
+    required      Field must be present
+    unique        Add a unique index
+    index         Add a regular index
+    min=N, max=N  Length bounds for strings, value bounds for numbers
+    default=V     Default value (quote it to include ':')
+
+  enum(a,b,c) types a field with a fixed set of values.
+
+Relations:
+  category:belongs_to      category_id key, picked with a searchable select
+  author:belongs_to(User)  when the model differs from the field name
+  tags:many_to_many        tag_ids in requests, picked with a multi-select
+
+  name_id:uint is a belongs_to relation once the Name resource exists.
+
+Attachments:
+  avatar:image             JPEG, PNG, GIF or WebP up to 5 MB, with a preview
+  resume:file:max=20       any file, max=N sets the limit in MB (default 10)
+
+Dates:
+  starts_on:date           date picker, filtered with ?starts_on_from=&starts_on_to=
+  starts_at:datetime       date and time picker in local time, sent as UTC
+  opens_at:time            time picker, HH:MM
+
+Arrays and JSON:
+  tags:[]string:max=10     tags input, stored as a JSON array
+  scores:[]int             tags input for whole numbers
+  meta:json                JSON object edited as text
+
+Money and decimals:
+  price:money(EUR)         exact amount in euros, 2 decimal places
+  rate:decimal(10,4)       10 digits, 4 of them after the decimal point
+
+Slugs:
+  slug:slug                unique slug derived from the title
+  path:slug(name)          derived from the name field
+
+Search, filters and sorting:
+  The list endpoint takes q to search the text fields, and per field
+  title=, title_contains=, status_in=a,b, views_gt=, views_lt= and sort=.
+  The index page keeps them in the URL and shows each filter as a chip.
+
+Display field:
+  Records are named in modals and select options by their title, name,
+  label or email field, or else their first string field. Choose another
+  with --display:
+    construct g Book isbn:string title:string --display isbn
 
 Syntax:
   g or generate    Generate both backend and frontend
   g:b or gen:b     Generate backend only
-  g:f or gen:f     Generate frontend only`,
+  g:f or gen:f     Generate frontend only
+
+Existing files:
+  Generated files are recorded in .construct/manifest.json. Regenerating
+  merges your edits with the new output, marking conflicts where both
+  changed the same lines. Files the manifest does not know about are never
+  overwritten unless you choose what to do with them:
+    --dry-run        List files that would be created, modified or skipped
+    --diff           Show a unified diff of every change (implies --dry-run)
+    --force          Overwrite files that differ
+    --skip-existing  Keep every file that already exists
+
+Schema files:
+  --from reads many resources from a YAML file and generates them in
+  dependency order. Re-running it only regenerates resources whose
+  definition changed since the last run.
+
+  resources:
+    - name: Category
+      fields:
+        - name:string:unique
+    - name: Post
+      fields:
+        - title:string:max=255
+        - body:text?
+      belongs_to: [Category]`,
 	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+
+		opts, err := generateOptions(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if from, _ := cmd.Flags().GetString("from"); from != "" {
+			if len(args) > 0 {
+				fmt.Println("❌ Error: --from cannot be combined with a resource name")
+				os.Exit(1)
+			}
+			if display, _ := cmd.Flags().GetString("display"); display != "" {
+				fmt.Println("❌ Error: --display cannot be combined with --from, set display: on the resources of the schema")
+				os.Exit(1)
+			}
+			runGenerateFromSchema(invokedName(cmd), from, opts)
+			return
+		}
+
 		// Validate args
 		if len(args) < 1 {
 			cmd.Help()
 			os.Exit(1)
 		}
-		runGenerate(cmd.Name(), args)
+		display, _ := cmd.Flags().GetString("display")
+		runGenerate(invokedName(cmd), args, display, opts)
 	},
 }
 
-func runGenerate(command string, args []string) {
+func init() {
+	generateCmd.Flags().String("from", "", "generate every resource described in a schema file")
+	generateCmd.Flags().String("display", "", "field naming a record in modals and select options")
+	generateCmd.Flags().Bool("dry-run", false, "list the files that would be created, modified or skipped")
+	generateCmd.Flags().Bool("diff", false, "show a unified diff of every change (implies --dry-run)")
+	generateCmd.Flags().Bool("force", false, "overwrite files that differ from the generated output")
+	generateCmd.Flags().Bool("skip-existing", false, "leave files that already exist untouched")
+}
+
+// generateOptions reads the file writing flags of a command
+func generateOptions(cmd *mamba.Command) (GenerateOptions, error) {
+	var opts GenerateOptions
+	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.Diff, _ = cmd.Flags().GetBool("diff")
+	opts.Force, _ = cmd.Flags().GetBool("force")
+	opts.SkipExisting, _ = cmd.Flags().GetBool("skip-existing")
+	if opts.Force && opts.SkipExisting {
+		return opts, fmt.Errorf("--force and --skip-existing cannot be combined")
+	}
+	return opts, nil
+}
+
+// generateTargets determines what to generate based on the command suffix
+func generateTargets(command string) (backend, frontend bool) {
+	switch {
+	case strings.HasSuffix(command, ":b"):
+		return true, false
+	case strings.HasSuffix(command, ":f"):
+		return false, true
+	default:
+		return true, true
+	}
+}
+
+func runGenerate(command string, args []string, display string, opts GenerateOptions) {
 	printBanner()
 
 	resourceName := args[0]
 	fields := args[1:]
 
 	root, err := findProjectRoot()
+	if err == nil {
+		err = loadProject(root)
+	}
 	if err != nil {
 		fmt.Printf("❌ Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Determine what to generate based on command suffix
-	generateBackend := true
-	generateFrontend := true
+	// Validate field definitions before touching any files
+	data, err := displayedTemplateData(resourceName, fields, display)
+	if err == nil {
+		err = data.checkSlugSources(nil)
+	}
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	// Check for :b or :f suffix
-	if strings.HasSuffix(command, ":b") {
-		generateBackend = true
-		generateFrontend = false
+	// Determine what to generate based on command suffix
+	generateBackend, generateFrontend := generateTargets(command)
+	if !generateFrontend {
 		fmt.Println("🔧 Generating backend only...")
-	} else if strings.HasSuffix(command, ":f") {
-		generateBackend = false
-		generateFrontend = true
+	} else if !generateBackend {
 		fmt.Println("🔧 Generating frontend only...")
 	} else {
 		fmt.Println("🔧 Generating full-stack CRUD...")
 	}
 	fmt.Println()
 
-	// Step 1: Generate Go backend (if needed)
+	// Render everything before writing so that a conflict in one file
+	// leaves the project untouched
+	var files []*generatedFile
 	if generateBackend {
-		fmt.Println("🔷 Generating Go backend...")
-		if err := GenerateBackend(root, resourceName, fields); err != nil {
+		backend, err := backendFiles(root, data)
+		if err != nil {
 			fmt.Printf("❌ Go generation failed: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println("✅ Go backend generated")
-		fmt.Println()
+		files = append(files, backend...)
 	}
-
-	// Step 2: Generate Vue frontend (if needed)
 	if generateFrontend {
-		fmt.Println("🟢 Generating Vue frontend...")
-		if err := GenerateFrontend(root, resourceName, fields); err != nil {
+		frontend, err := frontendFiles(root, data)
+		if err != nil {
 			fmt.Printf("❌ Vue generation failed: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println("✅ Vue frontend generated")
-		fmt.Println()
+		files = append(files, frontend...)
+	}
+
+	if err := writeFiles(root, files, opts); err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println()
+
+	if opts.preview() {
+		fmt.Println("🔍 Dry run, no files were written")
+		for _, f := range files {
+			if f.action == actionConflict {
+				fmt.Println("   Files marked ! differ from the generated output; use --force to overwrite or --skip-existing to keep them")
+				break
+			}
+		}
+		return
 	}
 
 	// Success message
@@ -99,17 +262,57 @@ func runGenerate(command string, args []string) {
 		fmt.Println("🎉 Frontend generated successfully!")
 	}
 
+	// The other side is generated from the same arguments
+	rest := strings.Join(fields, " ")
+	if display != "" {
+		rest += " --display " + display
+	}
+
 	fmt.Println()
 	fmt.Printf("📝 Next steps:\n")
 	if generateBackend && generateFrontend {
 		fmt.Printf("   1. Start dev servers: construct dev\n")
-		fmt.Printf("   2. Visit: http://localhost:3100/%s\n", strings.ToLower(pluralize(resourceName)))
-		fmt.Printf("   3. API available at: /api/%s\n", strings.ToLower(pluralize(resourceName)))
+		fmt.Printf("   2. Visit: http://localhost:3100%s\n", data.RoutePath)
+		fmt.Printf("   3. API available at: /api%s\n", data.RoutePath)
 	} else if generateBackend {
-		fmt.Printf("   1. Test API: curl http://localhost:8100/api/%s\n", strings.ToLower(pluralize(resourceName)))
-		fmt.Printf("   2. Generate frontend: construct g %s %s --frontend\n", resourceName, strings.Join(fields, " "))
+		fmt.Printf("   1. Test API: curl http://localhost:8100/api%s\n", data.RoutePath)
+		fmt.Printf("   2. Generate frontend: construct g:f %s %s\n", resourceName, rest)
 	} else {
-		fmt.Printf("   1. Generate backend: construct g %s %s --backend\n", resourceName, strings.Join(fields, " "))
+		fmt.Printf("   1. Generate backend: construct g:b %s %s\n", resourceName, rest)
 		fmt.Printf("   2. Start dev: construct dev\n")
 	}
 }
+
+func runGenerateFromSchema(command, path string, opts GenerateOptions) {
+	printBanner()
+
+	root, err := findProjectRoot()
+	if err == nil {
+		err = loadProject(root)
+	}
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	schema, err := LoadSchema(path)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	generateBackend, generateFrontend := generateTargets(command)
+	fmt.Printf("🔧 Generating %d resources from %s...\n", len(schema.Resources), path)
+	fmt.Println()
+
+	statuses, err := GenerateFromSchema(root, schema, generateBackend, generateFrontend, opts)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Println()
+		printSchemaReport(schema, statuses)
+		os.Exit(1)
+	}
+
+	fmt.Println("🎉 Schema applied:")
+	printSchemaReport(schema, statuses)
+}
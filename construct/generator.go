@@ -1,22 +1,22 @@
 package construct
 
 import (
-	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
-	"strings"
-	"text/template"
 )
 
 // TemplateData holds all data needed for code generation
 type TemplateData struct {
-	ResourceName     string
-	LowerResourceName string
-	PluralName       string
-	LowerPluralName  string
-	ModuleName       string
-	DisplayField     string
+	ResourceName     string // e.g. "BlogPost"
+	LowerResourceName string // e.g. "blogPost"
+	PluralName       string // e.g. "BlogPosts"
+	LowerPluralName  string // e.g. "blogPosts"
+	ModuleName       string // Go package and module key, e.g. "blog_posts"
+	DirName          string // Vue module directory, e.g. "blog-posts"
+	RoutePath        string // e.g. "/blog-posts"
+	HumanName        string // e.g. "blog post"
+	HumanPluralName  string // e.g. "blog posts"
+	DisplayField     string // Field naming a record, empty when none can, see display.go
 	Fields           []TemplateField
 }
 
@@ -29,73 +29,167 @@ type TemplateField struct {
 	TypeScriptType string
 	GoType         string
 	IsBool         bool
+	IsEnum         bool
+	IsDate         bool // date, datetime or time, see dates.go
+	IsNullable     bool // pointer in Go and "| null" in TypeScript, see nullable.go
+	IsJSON         bool // []string, []int or json, see collections.go
+	IsDecimal      bool // money or decimal, see decimals.go
+	IsSlug         bool // derived from SlugSource, see slugs.go
 	IsPointer      bool
 	Sortable       bool
 	ZeroValue      string
+	InitialValue   string // Initial form value in TypeScript
+	ItemValue      string // Form value read from an existing item, e.g. item.title
+	SubmitValue    string // Request value read from the submitted form, e.g. event.data.title
 	TrueLabel      string
 	FalseLabel     string
+	EnumValues     []EnumValue // Values of an enum field
+
+	// Relation to another resource, see relations.go
+	Relationship   string // belongs_to or many_to_many
+	RelatedModel   string // e.g. "Category"
+	RelatedName    string // Key of the related records in responses, e.g. "category"
+	RelatedRoute   string // e.g. "/categories"
+	RelatedDisplay string // Key of the display field of related records
+	RelatedType    string // TypeScript type of a related record
+
+	// Image or file stored as an attachment, see attachments.go
+	IsAttachment bool
+	MaxSize      int      // Size limit in megabytes
+	Accept       []string // Accepted MIME types, any when empty
+	AcceptLabel  string   // e.g. "JPEG, PNG, GIF or WebP"
+
+	// Exact decimal number, see decimals.go
+	Precision int    // Total digits
+	Scale     int    // Digits after the decimal point
+	Currency  string // ISO 4217 code of a money field, e.g. "USD"
+
+	// Slug derived from another field, see slugs.go
+	SlugSource string // Name of the source field, e.g. "title"
+
+	// Modifiers parsed from the field definition
+	Required   bool
+	Nullable   bool
+	Unique     bool
+	Index      bool
+	Min        string
+	Max        string
+	Default    string
+	HasDefault bool
+
+	// Derived from the modifiers
+	GORMTag    string // gorm struct tag for the model
+	BindingTag string // validator rules for create requests
+	ZodSchema  string // zod validator for the generated form
 }
 
-// NewTemplateData creates template data from resource name and fields
-func NewTemplateData(resourceName string, fieldArgs []string) *TemplateData {
-	pluralName := pluralize(resourceName)
-	displayField := "title" // Default, can be made smarter
+// EnumValue is one of the values of an enum field
+type EnumValue struct {
+	Value string // e.g. "in_review"
+	Label string // e.g. "In review"
+	Color string // Badge color on the index page
+}
+
+// NewTemplateData creates template data from resource name and fields,
+// displayed by the field chooseDisplayField picks
+func NewTemplateData(resourceName string, fieldArgs []string) (*TemplateData, error) {
+	fields, err := parseFieldsToTemplateFields(fieldArgs)
+	if err != nil {
+		return nil, err
+	}
+	return newTemplateData(resourceName, fields, fieldArgs, chooseDisplayField(fields))
+}
 
-	fields := parseFieldsToTemplateFields(fieldArgs)
+// NewTemplateDataWithDisplay creates template data displayed by the named
+// field, which the fields need not include, or by no field when empty
+func NewTemplateDataWithDisplay(resourceName string, fieldArgs []string, displayField string) (*TemplateData, error) {
+	fields, err := parseFieldsToTemplateFields(fieldArgs)
+	if err != nil {
+		return nil, err
+	}
+	return newTemplateData(resourceName, fields, fieldArgs, displayField)
+}
+
+func newTemplateData(resourceName string, fields []TemplateField, fieldArgs []string, displayField string) (*TemplateData, error) {
+	resourceName = toPascalCase(resourceName)
+	pluralName := pluralize(resourceName)
+	if pluralName == resourceName {
+		return nil, fmt.Errorf("%s is the same in the plural; choose another name or register its plural in %s", resourceName, inflectionsPath)
+	}
+	if err := resolveSlugSources(fields, fieldArgs, displayField); err != nil {
+		return nil, err
+	}
 
 	return &TemplateData{
 		ResourceName:      resourceName,
-		LowerResourceName: strings.ToLower(resourceName),
+		LowerResourceName: toCamelCase(resourceName),
 		PluralName:        pluralName,
-		LowerPluralName:   strings.ToLower(pluralName),
-		ModuleName:        strings.ToLower(pluralName),
+		LowerPluralName:   toCamelCase(pluralName),
+		ModuleName:        toSnakeCase(pluralName),
+		DirName:           toKebabCase(pluralName),
+		RoutePath:         "/" + toKebabCase(pluralName),
+		HumanName:         humanWords(resourceName),
+		HumanPluralName:   humanWords(pluralName),
 		DisplayField:      displayField,
 		Fields:            fields,
-	}
+	}, nil
 }
 
-func parseFieldsToTemplateFields(fieldArgs []string) []TemplateField {
+// parseFieldsToTemplateFields parses field definitions, failing on the first
+// malformed one instead of skipping it
+func parseFieldsToTemplateFields(fieldArgs []string) ([]TemplateField, error) {
 	var fields []TemplateField
+	seen := map[string]int{}
 
-	for _, f := range fieldArgs {
-		parts := strings.Split(f, ":")
-		if len(parts) < 2 {
-			continue
+	for i, f := range fieldArgs {
+		field, err := parseField(f)
+		if err != nil {
+			if fe, ok := err.(*FieldError); ok {
+				fe.Arg = f
+				fe.Index = i + 1
+			}
+			return nil, err
 		}
 
-		name := parts[0]
-		goType := parts[1]
-		tsType := mapGoTypeToTypeScript(goType)
-		isBool := goType == "bool" || goType == "boolean"
-		isPointer := false
-
-		// For update requests, booleans are pointers
-		if isBool {
-			isPointer = true
+		if prev, exists := seen[field.Name]; exists {
+			return nil, &FieldError{
+				Arg:        f,
+				Index:      i + 1,
+				Column:     1,
+				Token:      field.Name,
+				Message:    fmt.Sprintf("duplicate field %q", field.Name),
+				Suggestion: fmt.Sprintf("already defined by field #%d", prev),
+			}
 		}
+		seen[field.Name] = i + 1
 
-		fieldName := titleCase(name)
-		label := titleCase(name)
-
-		zeroValue := getZeroValue(goType)
-
-		fields = append(fields, TemplateField{
-			Name:           name,
-			FieldName:      fieldName,
-			Label:          label,
-			Type:           goType,
-			TypeScriptType: tsType,
-			GoType:         goType,
-			IsBool:         isBool,
-			IsPointer:      isPointer,
-			Sortable:       true,
-			ZeroValue:      zeroValue,
-			TrueLabel:      "Yes",
-			FalseLabel:     "No",
-		})
+		fields = append(fields, field)
 	}
 
-	return fields
+	return fields, nil
+}
+
+// newTemplateField creates a field with the defaults for its type
+func newTemplateField(name, goType string) TemplateField {
+	isBool := goType == "bool" || goType == "boolean"
+
+	return TemplateField{
+		Name:           name,
+		FieldName:      toPascalCase(name),
+		Label:          humanize(name),
+		Type:           goType,
+		TypeScriptType: mapGoTypeToTypeScript(goType),
+		GoType:         goType,
+		IsBool:         isBool,
+		IsPointer:      isBool, // For update requests, booleans are pointers
+		Sortable:       true,
+		ZeroValue:      getZeroValue(goType),
+		InitialValue:   getZeroValue(goType),
+		ItemValue:      "item." + name,
+		SubmitValue:    "event.data." + name,
+		TrueLabel:      "Yes",
+		FalseLabel:     "No",
+	}
 }
 
 func mapGoTypeToTypeScript(goType string) string {
@@ -106,8 +200,14 @@ func mapGoTypeToTypeScript(goType string) string {
 		return "number"
 	case "bool", "boolean":
 		return "boolean"
-	case "date", "datetime", "time":
+	case "date", "datetime", "time", "money", "decimal":
 		return "string"
+	case "[]string":
+		return "string[]"
+	case "[]int":
+		return "number[]"
+	case "json":
+		return "Record<string, unknown>"
 	default:
 		return "string"
 	}
@@ -121,81 +221,41 @@ func getZeroValue(goType string) string {
 		return "0"
 	case "bool", "boolean":
 		return "false"
+	case "[]string", "[]int":
+		return "[]"
+	case "json":
+		return "{}"
 	default:
 		return "\"\""
 	}
 }
 
 // GenerateVueFiles generates all Vue files for a structure
-func GenerateVueFiles(root, resourceName string, fields []string) error {
-	data := NewTemplateData(resourceName, fields)
-
-	vueDir := filepath.Join(root, "vue")
-	structureDir := filepath.Join(vueDir, "structures", data.LowerPluralName)
-
-	// Create structure directory
-	if err := os.MkdirAll(structureDir, 0755); err != nil {
-		return fmt.Errorf("failed to create structure directory: %w", err)
-	}
-
-	// Generate index.vue
-	if err := generateFromTemplate(
-		filepath.Join(structureDir, "index.vue"),
-		vueIndexTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate index.vue: %w", err)
-	}
-
-	// Generate composable.ts
-	if err := generateFromTemplate(
-		filepath.Join(structureDir, "composable.ts"),
-		vueComposableTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate composable.ts: %w", err)
+func GenerateVueFiles(root, resourceName string, fields []string, opts GenerateOptions) error {
+	data, err := NewTemplateData(resourceName, fields)
+	if err != nil {
+		return err
 	}
 
-	// Generate types.ts (in structure)
-	if err := generateFromTemplate(
-		filepath.Join(structureDir, "types.ts"),
-		vueTypesTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate types.ts: %w", err)
-	}
+	vueDir := filepath.Join(root, "vue")
+	structureDir := filepath.Join(vueDir, "structures", data.DirName)
 
-	// Also generate types in view/types for global access
-	globalTypesDir := filepath.Join(vueDir, "view", "types")
-	if err := os.MkdirAll(globalTypesDir, 0755); err != nil {
-		return fmt.Errorf("failed to create types directory: %w", err)
+	templates := []fileTemplate{
+		projectTemplate(root, filepath.Join(structureDir, "index.vue"), "frontend/index.vue"),
+		projectTemplate(root, filepath.Join(structureDir, "api.ts"), "frontend/api.ts"),
+		projectTemplate(root, filepath.Join(structureDir, "types.ts"), "frontend/types.ts"),
+		// Also generate types in view/types for global access
+		projectTemplate(root, filepath.Join(vueDir, "view", "types", data.LowerResourceName+".ts"), "frontend/types.ts"),
 	}
 
-	if err := generateFromTemplate(
-		filepath.Join(globalTypesDir, data.LowerResourceName+".ts"),
-		vueTypesTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate global types: %w", err)
+	var files []*generatedFile
+	for _, t := range templates {
+		file, err := renderFileTemplate(root, t, data)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
 	}
 
-	return nil
+	return writeFiles(root, files, opts)
 }
-
-func generateFromTemplate(outputPath, templateContent string, data interface{}) error {
-	tmpl, err := template.New("").Parse(templateContent)
-	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
-}
\ No newline at end of file
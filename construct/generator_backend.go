@@ -1,100 +1,281 @@
 package construct
 
 import (
+	"bytes"
 	"fmt"
-	"os"
-	"os/exec"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"path/filepath"
 	"strings"
 )
 
-// GenerateBackend generates Go backend files using Base CLI
-func GenerateBackend(root, resourceName string, fields []string) error {
-	// Use base CLI to generate backend in api/ directory
-	args := append([]string{"generate", resourceName}, fields...)
-
-	cmd := exec.Command("base", args...)
-	cmd.Dir = root
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	// Set environment to tell base to use api/ instead of app/
-	cmd.Env = append(os.Environ(), "BASE_MODULE_DIR=api")
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("base CLI failed: %w", err)
-	}
+// BackendTemplateData holds the data used by the Go backend templates. It is
+// derived from TemplateData so backend and frontend agree on naming.
+type BackendTemplateData struct {
+	ModulePath            string // Go module path of the project, e.g. "base"
+	PackageName           string // Go package of the module, e.g. "posts"
+	Model                 string // e.g. "Post"
+	ModelLower            string // e.g. "post"
+	ModelSnake            string // e.g. "blog_post"
+	Plural                string // e.g. "Posts"
+	Service               string // e.g. "PostService"
+	Controller            string // e.g. "PostController"
+	RoutePath             string // e.g. "/posts"
+	TableName             string // e.g. "posts"
+	DisplayField          string // JSON name of the display field, empty when none
+	DisplayName           string // Go name of the display field, e.g. "Title"
+	HasImageField         bool
+	HasTranslatableFields bool
+	Fields                []BackendField
+}
 
-	// Move from app/ to api/ if base created in app/
-	appModulePath := filepath.Join(root, "app", strings.ToLower(pluralize(resourceName)))
-	apiModulePath := filepath.Join(root, "api", strings.ToLower(pluralize(resourceName)))
+// BackendField represents a field in the Go templates
+type BackendField struct {
+	Name         string // PascalCase Go field name
+	JSONName     string // snake_case JSON and column name
+	Type         string // Go type
+	IsRequired   bool
+	IsNullable   bool   // Update requests read it into a models.Nullable
+	IsPointer    bool   // The model stores a pointer, see ModelType
+	IsJSON       bool   // Stored with the JSON serializer
+	IsSlug       bool   // Derived from SlugSource by the service
+	Sortable     bool   // The list endpoint sorts by it
+	Filter       string // How the list endpoint filters it, see filters.go
+	SlugSource   string // Go name of the field a slug is derived from
+	Precision    int    // Digits of a Decimal
+	Scale        int    // Digits of a Decimal after the decimal point
+	IsRelation   bool
+	Relationship string // belongs_to, has_many, has_one or many_to_many
+	RelatedModel string
+	JoinModel    string // Join table struct of a many_to_many field, e.g. ArticleTag
+	JoinTable    string // e.g. article_tags
+	GORMTag      string
+	BindingTag   string
+	UpdateTag    string      // Binding of update requests, see updateBindingTag
+	EnumValues   []EnumValue // Values of an enum field, whose Type is declared with the model
+	MaxSize      int         // Size limit in megabytes of an attachment
+	Accept       []string    // MIME types accepted by an attachment, any when empty
+	AcceptLabel  string
+}
 
-	if _, err := os.Stat(appModulePath); err == nil {
-		// Move module from app/ to api/
-		if err := os.Rename(appModulePath, apiModulePath); err != nil {
-			return fmt.Errorf("failed to move module to api/: %w", err)
+// NewBackendTemplateData derives the backend template data from the
+// frontend template data
+func NewBackendTemplateData(modulePath string, data *TemplateData) *BackendTemplateData {
+	fields := make([]BackendField, 0, len(data.Fields))
+	hasAttachment := false
+	for _, f := range data.Fields {
+		field := BackendField{
+			Name:       f.FieldName,
+			JSONName:   f.Name,
+			Type:       mapFieldTypeToGo(f.Type),
+			IsRequired: f.Required,
+			IsNullable: f.IsNullable,
+			IsPointer:  f.isPointer(),
+			IsJSON:     f.IsJSON,
+			IsSlug:     f.IsSlug,
+			Sortable:   f.Sortable,
+			Filter:     f.Filter(),
+			Precision:  f.Precision,
+			Scale:      f.Scale,
+			GORMTag:    f.GORMTag,
+			BindingTag: f.BindingTag,
+			UpdateTag:  updateBindingTag(f.BindingTag),
+			EnumValues: f.EnumValues,
 		}
-
-		// Update api/init.go instead of app/init.go
-		updateInitFile(filepath.Join(root, "api", "init.go"), resourceName)
+		if f.IsAttachment {
+			hasAttachment = true
+			field.MaxSize = f.MaxSize
+			field.Accept = f.Accept
+			field.AcceptLabel = f.AcceptLabel
+		}
+		if f.IsEnum {
+			field.Type = data.ResourceName + f.FieldName
+		}
+		if f.IsSlug {
+			field.SlugSource = toPascalCase(f.SlugSource)
+		}
+		switch f.Relationship {
+		case "belongs_to":
+			field.Type = "uint"
+		case "many_to_many":
+			// Named after the field, so two fields relating the same model
+			// get a join table each
+			field.JSONName = f.RelatedName
+			field.Type = "[]*" + f.RelatedModel
+			field.JoinModel = data.ResourceName + toPascalCase(singularize(f.RelatedName))
+			field.JoinTable = toSnakeCase(data.ResourceName) + "_" + toSnakeCase(f.RelatedName)
+		}
+		if f.Relationship != "" {
+			field.IsRelation = true
+			field.Relationship = f.Relationship
+			field.RelatedModel = f.RelatedModel
+		}
+		fields = append(fields, field)
 	}
 
-	return nil
+	return &BackendTemplateData{
+		ModulePath:    modulePath,
+		PackageName:   data.ModuleName,
+		Model:         data.ResourceName,
+		ModelLower:    data.LowerResourceName,
+		ModelSnake:    toSnakeCase(data.ResourceName),
+		Plural:        data.PluralName,
+		Service:       data.ResourceName + "Service",
+		Controller:    data.ResourceName + "Controller",
+		RoutePath:     data.RoutePath,
+		TableName:     toSnakeCase(data.PluralName),
+		DisplayField:  data.DisplayField,
+		DisplayName:   toPascalCase(data.DisplayField),
+		HasImageField: hasAttachment,
+		Fields:        fields,
+	}
 }
 
-func updateInitFile(initPath string, resourceName string) error {
-	moduleName := strings.ToLower(pluralize(resourceName))
+// mapFieldTypeToGo maps a field type to the Go type used in the model
+func mapFieldTypeToGo(fieldType string) string {
+	switch fieldType {
+	case "string", "text", "email", "url", "time", "slug":
+		return "string"
+	case "float":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "date", "datetime":
+		return "types.DateTime"
+	case "json":
+		return "map[string]any"
+	case "money", "decimal":
+		return "Decimal"
+	case "image", "file":
+		return "*storage.Attachment"
+	default:
+		return fieldType
+	}
+}
 
-	// Read current init.go
-	content, err := os.ReadFile(initPath)
+// GenerateBackend generates the Go model, service, controller, validator and
+// module for a resource from the embedded base templates
+func GenerateBackend(root string, data *TemplateData, opts GenerateOptions) error {
+	files, err := backendFiles(root, data)
 	if err != nil {
 		return err
 	}
+	return writeFiles(root, files, opts)
+}
 
-	contentStr := string(content)
-
-	// Check if module already exists
-	moduleInit := fmt.Sprintf("modules[\"%s\"] = %s.Init(deps)", moduleName, moduleName)
-	if strings.Contains(contentStr, moduleInit) {
-		fmt.Printf("✅ Module already registered in api/init.go\n")
-		return nil // Already added
+// backendFiles renders the Go files for a resource along with the updated
+// api/init.go that registers its module
+func backendFiles(root string, data *TemplateData) ([]*generatedFile, error) {
+	modulePath, err := projectModulePath(root)
+	if err != nil {
+		return nil, err
 	}
+	backendData := NewBackendTemplateData(modulePath, data)
 
-	// Add import
-	importLine := fmt.Sprintf("\"base/api/%s\"", moduleName)
-	if !strings.Contains(contentStr, importLine) {
-		// Find the import block and add the import
-		importBlockStart := strings.Index(contentStr, "import (")
-		if importBlockStart == -1 {
-			return fmt.Errorf("could not find import block in api/init.go")
+	var files []*generatedFile
+	for _, t := range backendTemplates(root, backendData) {
+		file, err := renderFileTemplate(root, t, backendData)
+		if err != nil {
+			return nil, err
 		}
+		files = append(files, file)
+	}
 
-		// Find the end of existing imports (before ")")
-		importBlockEnd := strings.Index(contentStr[importBlockStart:], ")")
-		if importBlockEnd == -1 {
-			return fmt.Errorf("could not find end of import block in api/init.go")
+	for _, shared := range sharedBackendFiles {
+		file, err := shared(root, backendData)
+		if err != nil {
+			return nil, err
 		}
-		importBlockEnd += importBlockStart
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+
+	initPath := filepath.Join(root, "api", "init.go")
+	if !fileExists(initPath) {
+		fmt.Printf("  ⚠️  api/init.go not found, register the %s module manually\n", data.ModuleName)
+		return files, nil
+	}
+	content, err := registeredSource(initPath, modulePath, data.ModuleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register module in api/init.go: %w", err)
+	}
+	init := newGeneratedFile(root, initPath, content)
+	init.Patch = true
+	return append(files, init), nil
+}
+
+// sharedBackendFiles render the models that the fields of every resource
+// share, each returning nil when the fields do not use it
+var sharedBackendFiles = []func(root string, data *BackendTemplateData) (*generatedFile, error){nullableFile, decimalFile, slugFile}
+
+// backendTemplates lists the Go files generated for a resource
+func backendTemplates(root string, data *BackendTemplateData) []fileTemplate {
+	apiDir := filepath.Join(root, "api")
+	moduleDir := filepath.Join(apiDir, data.PackageName)
 
-		// Insert new import before the closing )
-		newImport := fmt.Sprintf("\t%s\n", importLine)
-		contentStr = contentStr[:importBlockEnd] + newImport + contentStr[importBlockEnd:]
+	return []fileTemplate{
+		projectTemplate(root, filepath.Join(apiDir, "models", data.ModelSnake+".go"), "base/model.tmpl"),
+		projectTemplate(root, filepath.Join(moduleDir, "service.go"), "base/service.tmpl"),
+		projectTemplate(root, filepath.Join(moduleDir, "controller.go"), "base/controller.tmpl"),
+		projectTemplate(root, filepath.Join(moduleDir, "validator.go"), "base/validator.tmpl"),
+		projectTemplate(root, filepath.Join(moduleDir, "module.go"), "base/module.tmpl"),
 	}
+}
 
-	// Add module initialization
-	returnIndex := strings.Index(contentStr, "return modules")
-	if returnIndex == -1 {
-		return fmt.Errorf("could not find 'return modules' in api/init.go")
+// formatGoSource drops imports the rendered code does not use, since the
+// templates import packages for optional features unconditionally, and
+// formats the result with gofmt. Unused imports are cut along with their
+// lines so they leave no gaps between the imports around them.
+func formatGoSource(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
 	}
 
-	// Insert the module initialization before return
-	moduleInitLine := fmt.Sprintf("\t// %s module\n\t%s\n\n\t", titleCase(moduleName), moduleInit)
-	contentStr = contentStr[:returnIndex] + moduleInitLine + contentStr[returnIndex:]
+	used := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
 
-	// Write back to file
-	if err := os.WriteFile(initPath, []byte(contentStr), 0644); err != nil {
-		return fmt.Errorf("failed to write api/init.go: %w", err)
+	var kept bytes.Buffer
+	last := 0
+	for _, imp := range file.Imports {
+		path := strings.Trim(imp.Path.Value, `"`)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == "_" || name == "." || used[name] {
+			continue
+		}
+		start := bytes.LastIndexByte(src[:fset.Position(imp.Pos()).Offset], '\n') + 1
+		end := fset.Position(imp.End()).Offset
+		if i := bytes.IndexByte(src[end:], '\n'); i >= 0 {
+			end += i + 1
+		} else {
+			end = len(src)
+		}
+		kept.Write(src[last:start])
+		last = end
 	}
+	kept.Write(src[last:])
 
-	fmt.Printf("✅ Added module to api/init.go\n")
-	return nil
+	file, err = parser.ParseFile(fset, "", kept.Bytes(), parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
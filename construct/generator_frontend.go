@@ -1,113 +1,61 @@
 package construct
 
-import (
-	"bytes"
-	"fmt"
-	"os"
-	"path/filepath"
-	"text/template"
-)
+import "path/filepath"
 
 // GenerateFrontend generates all Vue frontend files in self-contained module structure
-func GenerateFrontend(root, resourceName string, fields []string) error {
-	data := NewTemplateData(resourceName, fields)
-
-	// Self-contained module under app/{module}/
-	moduleDir := filepath.Join(root, "vue", "app", data.LowerPluralName)
-
-	// 1. Generate types in app/{module}/types/
-	if err := generateFileFromTemplate(
-		filepath.Join(moduleDir, "types", data.LowerResourceName+".ts"),
-		vueTypesTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate types: %w", err)
-	}
-	fmt.Printf("  ✓ Generated app/%s/types/%s.ts\n", data.LowerPluralName, data.LowerResourceName)
-
-	// 2. Generate composable in app/{module}/composables/
-	if err := generateFileFromTemplate(
-		filepath.Join(moduleDir, "composables", "use"+data.PluralName+".ts"),
-		vueComposableTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate composable: %w", err)
-	}
-	fmt.Printf("  ✓ Generated app/%s/composables/use%s.ts\n", data.LowerPluralName, data.PluralName)
-
-	// 3. Generate store in app/{module}/stores/
-	if err := generateFileFromTemplate(
-		filepath.Join(moduleDir, "stores", data.LowerPluralName+".ts"),
-		vueStoreTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate store: %w", err)
-	}
-	fmt.Printf("  ✓ Generated app/%s/stores/%s.ts\n", data.LowerPluralName, data.LowerPluralName)
-
-	// 4. Generate modal components in app/{module}/components/
-	componentsDir := filepath.Join(moduleDir, "components")
-	if err := os.MkdirAll(componentsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create components directory: %w", err)
-	}
-
-	// Add Modal
-	if err := generateFileFromTemplate(
-		filepath.Join(componentsDir, data.PluralName+"AddModal.vue"),
-		vueAddModalTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate AddModal: %w", err)
-	}
-	fmt.Printf("  ✓ Generated app/%s/components/%sAddModal.vue\n", data.LowerPluralName, data.PluralName)
-
-	// Delete Modal
-	if err := generateFileFromTemplate(
-		filepath.Join(componentsDir, data.PluralName+"DeleteModal.vue"),
-		vueDeleteModalTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate DeleteModal: %w", err)
-	}
-	fmt.Printf("  ✓ Generated app/%s/components/%sDeleteModal.vue\n", data.LowerPluralName, data.PluralName)
-
-	// 5. Generate main page (index) in app/{module}/pages/
-	if err := generateFileFromTemplate(
-		filepath.Join(moduleDir, "pages", "index.vue"),
-		vueIndexTemplate,
-		data,
-	); err != nil {
-		return fmt.Errorf("failed to generate page: %w", err)
+func GenerateFrontend(root string, data *TemplateData, opts GenerateOptions) error {
+	files, err := frontendFiles(root, data)
+	if err != nil {
+		return err
 	}
-	fmt.Printf("  ✓ Generated app/%s/pages/index.vue\n", data.LowerPluralName)
+	return writeFiles(root, files, opts)
+}
 
-	return nil
+// frontendFiles renders the Vue files for a resource under vue/app/{module}/
+func frontendFiles(root string, data *TemplateData) ([]*generatedFile, error) {
+	var files []*generatedFile
+	for _, t := range frontendTemplates(root, data) {
+		file, err := renderFileTemplate(root, t, data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	for _, shared := range sharedFrontendFiles {
+		file, err := shared(root, data)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+	return files, nil
 }
 
-func generateFileFromTemplate(outputPath, templateContent string, data interface{}) error {
-	// Parse template
-	tmpl, err := template.New("").Parse(templateContent)
-	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
-	}
+// sharedFrontendFiles render the helpers that every resource shares, each
+// returning nil when the fields do not use it
+var sharedFrontendFiles = []func(root string, data *TemplateData) (*generatedFile, error){requestsFile, datesFile, jsonFile, decimalsFile, slugsFile}
 
-	// Execute template
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
-	}
+// requestsFile renders utils/requests.ts, which the API modules of every
+// resource read responses and errors with
+func requestsFile(root string, data *TemplateData) (*generatedFile, error) {
+	t := projectTemplate(root, filepath.Join(root, "vue", "app", "utils", "requests.ts"), "frontend/requests.ts")
+	return renderFileTemplate(root, t, data)
+}
 
-	// Create directory if it doesn't exist
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", dir, err)
-	}
+// frontendTemplates lists the Vue files generated for a resource, in a
+// self-contained module under vue/app/{module}/
+func frontendTemplates(root string, data *TemplateData) []fileTemplate {
+	moduleDir := filepath.Join(root, "vue", "app", data.DirName)
 
-	// Write file
-	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	return []fileTemplate{
+		projectTemplate(root, filepath.Join(moduleDir, "types", data.LowerResourceName+".ts"), "frontend/types.ts"),
+		projectTemplate(root, filepath.Join(moduleDir, "api", data.LowerPluralName+".ts"), "frontend/api.ts"),
+		projectTemplate(root, filepath.Join(moduleDir, "stores", data.LowerPluralName+".ts"), "frontend/store.ts"),
+		projectTemplate(root, filepath.Join(moduleDir, "components", data.PluralName+"AddModal.vue"), "frontend/AddModal.vue"),
+		projectTemplate(root, filepath.Join(moduleDir, "components", data.PluralName+"DeleteModal.vue"), "frontend/DeleteModal.vue"),
+		projectTemplate(root, filepath.Join(moduleDir, "pages", "index.vue"), "frontend/index.vue"),
 	}
-
-	return nil
 }
-
@@ -0,0 +1,234 @@
+package construct
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goSource is a parsed Go file that is edited by splicing its source, so
+// that everything but the edited lines keeps its original layout
+type goSource struct {
+	fset *token.FileSet
+	file *ast.File
+	src  []byte
+	path string
+}
+
+// sourceEdit replaces src[start:end] with text
+type sourceEdit struct {
+	start, end int
+	text       string
+}
+
+// loadGoSource reads and parses a Go file
+func loadGoSource(path string) (*goSource, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseGoSource(path, src)
+}
+
+// parseGoSource parses Go source; path is used in messages
+func parseGoSource(path string, src []byte) (*goSource, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return &goSource{fset: fset, file: file, src: src, path: path}, nil
+}
+
+// importName returns the local name of an import, if present
+func (s *goSource) importName(importPath string) (string, bool) {
+	for _, imp := range s.file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != importPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name, true
+		}
+		return importPath[strings.LastIndex(importPath, "/")+1:], true
+	}
+	return "", false
+}
+
+// nameInUse reports whether an import already uses the given local name
+func (s *goSource) nameInUse(name string) bool {
+	for _, imp := range s.file.Imports {
+		path := strings.Trim(imp.Path.Value, `"`)
+		local := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			local = imp.Name.Name
+		}
+		if local == name {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesPackage reports whether the file refers to pkg.Something
+func (s *goSource) referencesPackage(pkg string) bool {
+	found := false
+	ast.Inspect(s.file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == pkg {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// addImport returns the edit adding an import, handling grouped, single-line
+// and missing import declarations
+func (s *goSource) addImport(importPath, pkgName, defaultName string) sourceEdit {
+	spec := strconv.Quote(importPath)
+	if pkgName != defaultName {
+		spec = pkgName + " " + spec
+	}
+
+	var last *ast.GenDecl
+	for _, decl := range s.file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			last = gen
+		}
+	}
+
+	if last == nil {
+		offset := s.offset(s.file.Name.End())
+		return sourceEdit{start: offset, end: offset, text: "\n\nimport " + spec}
+	}
+
+	if last.Lparen.IsValid() {
+		offset := s.offset(last.Rparen)
+		return sourceEdit{start: offset, end: offset, text: "\t" + spec + "\n"}
+	}
+
+	// Turn a single-line import into a group, keeping a comment that
+	// follows it on its line attached to it
+	end := s.offset(last.End())
+	if rest := bytes.TrimRight(s.src[end:s.lineEnd(end)], "\r\n"); bytes.HasPrefix(bytes.TrimSpace(rest), []byte("//")) {
+		end += len(rest)
+	}
+	existing := string(s.src[s.offset(last.Specs[0].Pos()):end])
+	return sourceEdit{
+		start: s.offset(last.Pos()),
+		end:   end,
+		text:  "import (\n\t" + existing + "\n\t" + spec + "\n)",
+	}
+}
+
+// addImportBeside adds an import next to prev or next, the imports around it
+// in the same group of another file, so it lands in the right group; it
+// falls back to addImport when neither is imported here
+func (s *goSource) addImportBeside(importPath, pkgName, defaultName, prev, next string) sourceEdit {
+	spec := strconv.Quote(importPath)
+	if pkgName != defaultName {
+		spec = pkgName + " " + spec
+	}
+	for _, imp := range s.file.Imports {
+		switch strings.Trim(imp.Path.Value, `"`) {
+		case prev:
+			offset := s.offset(imp.End())
+			return sourceEdit{start: offset, end: offset, text: "\n\t" + spec}
+		case next:
+			offset := s.offset(imp.Pos())
+			return sourceEdit{start: offset, end: offset, text: spec + "\n\t"}
+		}
+	}
+	return s.addImport(importPath, pkgName, defaultName)
+}
+
+// render applies edits to the source and formats the result. Edits at the
+// same offset are inserted in the order given.
+func (s *goSource) render(edits []sourceEdit) ([]byte, error) {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	// Edits within a range another edit replaces, such as the element of a
+	// literal in a removed statement, are dropped
+	var kept []sourceEdit
+	coverStart, coverEnd := 0, -1
+	for _, e := range edits {
+		inside := e.start >= coverStart && e.end <= coverEnd && (e.end > e.start || e.start > coverStart && e.start < coverEnd)
+		if inside {
+			continue
+		}
+		kept = append(kept, e)
+		if e.end > e.start && e.end > coverEnd {
+			coverStart, coverEnd = e.start, e.end
+		}
+	}
+	edits = kept
+
+	src := append([]byte{}, s.src...)
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		var buf bytes.Buffer
+		buf.Write(src[:e.start])
+		buf.WriteString(e.text)
+		buf.Write(src[e.end:])
+		src = buf.Bytes()
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", filepath.Base(s.path), err)
+	}
+	return formatted, nil
+}
+
+// write applies edits to the source and writes it back
+func (s *goSource) write(edits []sourceEdit) error {
+	content, err := s.render(edits)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, content, 0644)
+}
+
+// offset converts a position to a byte offset in the source
+func (s *goSource) offset(pos token.Pos) int {
+	return s.fset.Position(pos).Offset
+}
+
+// lineStart returns the offset of the start of the line containing offset
+func (s *goSource) lineStart(offset int) int {
+	return bytes.LastIndexByte(s.src[:offset], '\n') + 1
+}
+
+// lineEnd returns the offset just past the newline ending the line
+// containing offset
+func (s *goSource) lineEnd(offset int) int {
+	if i := bytes.IndexByte(s.src[offset:], '\n'); i >= 0 {
+		return offset + i + 1
+	}
+	return len(s.src)
+}
+
+// text returns the source of a node
+func (s *goSource) text(node ast.Node) string {
+	return string(s.src[s.offset(node.Pos()):s.offset(node.End())])
+}
+
+// lines returns the offsets of the whole lines spanned by a node
+func (s *goSource) lines(node ast.Node) (int, int) {
+	return s.lineStart(s.offset(node.Pos())), s.lineEnd(s.offset(node.End()))
+}
+
+// errorf reports a diagnostic at a position in the file
+func (s *goSource) errorf(pos token.Pos, format string, args ...interface{}) error {
+	p := s.fset.Position(pos)
+	return fmt.Errorf("%s:%d: %s", filepath.Base(p.Filename), p.Line, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,367 @@
+package construct
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// inflectionsPath lets a project register its own inflections:
+//
+//	irregular:
+//	  cactus: cacti
+//	uncountable:
+//	  - metadata
+//	acronyms:
+//	  - SKU
+//	plural:
+//	  - match: "(?i)(quiz)$"
+//	    replace: "${1}zes"
+//	singular:
+//	  - match: "(?i)(quiz)zes$"
+//	    replace: "${1}"
+const inflectionsPath = ".construct/inflections.yaml"
+
+// inflectionRule rewrites a word that matches a pattern
+type inflectionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Inflections holds the rules for pluralizing and singularizing words and
+// the acronyms kept uppercase in Go names
+type Inflections struct {
+	plurals     []inflectionRule // Later rules take precedence
+	singulars   []inflectionRule
+	irregular   map[string]string // Singular to plural
+	singularOf  map[string]string // Plural to singular
+	uncountable map[string]bool
+	acronyms    map[string]bool
+}
+
+// inflections are the rules in use, the defaults plus those of the project
+var inflections = defaultInflections()
+
+func defaultInflections() *Inflections {
+	in := &Inflections{
+		irregular:   map[string]string{},
+		singularOf:  map[string]string{},
+		uncountable: map[string]bool{},
+		acronyms:    map[string]bool{},
+	}
+
+	for _, r := range [][2]string{
+		{`$`, "s"},
+		{`s$`, "ses"}, // Singular words ending in s, e.g. bonus, canvas, lens
+		{`^(ax|test)is$`, "${1}es"},
+		{`(octop|vir)us$`, "${1}i"},
+		{`(octop|vir)i$`, "${1}i"},
+		{`(alias|status|campus)$`, "${1}es"},
+		{`(criteri|phenomen)on$`, "${1}a"},
+		{`(bu)s$`, "${1}ses"},
+		{`(buffal|tomat|potat|her|ech)o$`, "${1}oes"},
+		{`([ti])um$`, "${1}a"},
+		{`([ti])a$`, "${1}a"},
+		{`sis$`, "ses"},
+		{`(?:([^f])fe|([lr])f)$`, "${1}${2}ves"},
+		{`(hive)$`, "${1}s"},
+		{`([^aeiouy]|qu)y$`, "${1}ies"},
+		{`(x|ch|ss|sh|z)$`, "${1}es"},
+		{`(matr|vert|ind)(?:ix|ex)$`, "${1}ices"},
+		{`^(m|l)ouse$`, "${1}ice"},
+		{`^(m|l)ice$`, "${1}ice"},
+		{`^(ox)$`, "${1}en"},
+		{`^(oxen)$`, "${1}"},
+		{`(quiz)$`, "${1}zes"},
+	} {
+		in.plurals = append(in.plurals, inflectionRule{regexp.MustCompile("(?i)" + r[0]), r[1]})
+	}
+
+	for _, r := range [][2]string{
+		{`s$`, ""},
+		{`(ss)$`, "${1}"},
+		{`(n)ews$`, "${1}ews"},
+		{`([ti])a$`, "${1}um"},
+		{`((a)naly|(b)a|(d)iagno|(p)arenthe|(p)rogno|(s)ynop|(t)he)(sis|ses)$`, "${1}sis"},
+		{`(^analy)(sis|ses)$`, "${1}sis"},
+		{`([^f])ves$`, "${1}fe"},
+		{`(hive)s$`, "${1}"},
+		{`(tive)s$`, "${1}"},
+		{`([lr])ves$`, "${1}f"},
+		{`([^aeiouy]|qu)ies$`, "${1}y"},
+		{`(s)eries$`, "${1}eries"},
+		{`(m)ovies$`, "${1}ovie"},
+		{`(x|ch|ss|sh|z)es$`, "${1}"},
+		{`^(m|l)ice$`, "${1}ouse"},
+		{`(bus)(es)?$`, "${1}"},
+		{`(o)es$`, "${1}"},
+		{`(shoe)s$`, "${1}"},
+		{`(cris|test)(is|es)$`, "${1}is"},
+		{`^(a)x[ie]s$`, "${1}xis"},
+		{`(octop|vir)(us|i)$`, "${1}us"},
+		{`(alias|status|campus|bonus|cactus|census|canvas|atlas)(es)?$`, "${1}"},
+		{`^(gas|lens|iris|sms)(es)?$`, "${1}"},
+		{`(criteri|phenomen)a$`, "${1}on"},
+		{`^(ox)en`, "${1}"},
+		{`(vert|ind)ices$`, "${1}ex"},
+		{`(matr)ices$`, "${1}ix"},
+		{`(quiz)zes$`, "${1}"},
+		{`(database)s$`, "${1}"},
+	} {
+		in.singulars = append(in.singulars, inflectionRule{regexp.MustCompile("(?i)" + r[0]), r[1]})
+	}
+
+	for singular, plural := range map[string]string{
+		"person": "people",
+		"man":    "men",
+		"woman":  "women",
+		"child":  "children",
+		"sex":    "sexes",
+		"move":   "moves",
+		"zombie": "zombies",
+		"foot":   "feet",
+		"tooth":  "teeth",
+		"goose":  "geese",
+	} {
+		in.addIrregular(singular, plural)
+	}
+
+	for _, word := range []string{
+		"equipment", "information", "rice", "money", "species", "series",
+		"fish", "sheep", "jeans", "police", "news", "metadata", "feedback",
+		"software", "hardware", "staff", "data", "chassis",
+	} {
+		in.uncountable[word] = true
+	}
+
+	// Go initialisms, see https://go.dev/wiki/CodeReviewComments#initialisms.
+	// id is left out: Go names follow the framework's Id, e.g. CategoryId.
+	for _, word := range []string{
+		"api", "css", "html", "http", "https", "ip", "json", "sql",
+		"uri", "url", "uuid", "xml",
+	} {
+		in.acronyms[word] = true
+	}
+
+	return in
+}
+
+func (in *Inflections) addIrregular(singular, plural string) {
+	singular, plural = strings.ToLower(singular), strings.ToLower(plural)
+	in.irregular[singular] = plural
+	in.singularOf[plural] = singular
+}
+
+// loadInflections adds the project's custom inflections, if it has any, to
+// the defaults
+func loadInflections(root string) error {
+	inflections = defaultInflections()
+
+	content, err := os.ReadFile(filepath.Join(root, inflectionsPath))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var custom struct {
+		Irregular   map[string]string `yaml:"irregular"`
+		Uncountable []string          `yaml:"uncountable"`
+		Acronyms    []string          `yaml:"acronyms"`
+		Plural      []struct {
+			Match   string `yaml:"match"`
+			Replace string `yaml:"replace"`
+		} `yaml:"plural"`
+		Singular []struct {
+			Match   string `yaml:"match"`
+			Replace string `yaml:"replace"`
+		} `yaml:"singular"`
+	}
+	if err := yaml.Unmarshal(content, &custom); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", inflectionsPath, err)
+	}
+
+	for singular, plural := range custom.Irregular {
+		inflections.addIrregular(singular, plural)
+	}
+	for _, word := range custom.Uncountable {
+		inflections.uncountable[strings.ToLower(word)] = true
+	}
+	for _, word := range custom.Acronyms {
+		inflections.acronyms[strings.ToLower(word)] = true
+	}
+	for _, r := range custom.Plural {
+		pattern, err := regexp.Compile(r.Match)
+		if err != nil {
+			return fmt.Errorf("invalid plural rule in %s: %w", inflectionsPath, err)
+		}
+		inflections.plurals = append(inflections.plurals, inflectionRule{pattern, r.Replace})
+	}
+	for _, r := range custom.Singular {
+		pattern, err := regexp.Compile(r.Match)
+		if err != nil {
+			return fmt.Errorf("invalid singular rule in %s: %w", inflectionsPath, err)
+		}
+		inflections.singulars = append(inflections.singulars, inflectionRule{pattern, r.Replace})
+	}
+	return nil
+}
+
+// Pluralize returns the plural of a name, inflecting its last word, e.g.
+// BlogPost becomes BlogPosts and blog_category becomes blog_categories
+func (in *Inflections) Pluralize(name string) string {
+	return in.inflectLast(name, in.irregular, in.singularOf, in.plurals)
+}
+
+// Singularize returns the singular of a name, inflecting its last word
+func (in *Inflections) Singularize(name string) string {
+	return in.inflectLast(name, in.singularOf, in.irregular, in.singulars)
+}
+
+func (in *Inflections) inflectLast(name string, irregular, inflected map[string]string, rules []inflectionRule) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return name
+	}
+	last := words[len(words)-1]
+	at := strings.LastIndex(name, last)
+	return name[:at] + in.inflect(last, irregular, inflected, rules) + name[at+len(last):]
+}
+
+// inflect inflects a single word, keeping the case of its unchanged prefix.
+// Irregular words that are already inflected, such as people, are kept.
+func (in *Inflections) inflect(word string, irregular, inflected map[string]string, rules []inflectionRule) string {
+	lower := strings.ToLower(word)
+	if _, done := inflected[lower]; done || in.uncountable[lower] {
+		return word
+	}
+
+	result, ok := irregular[lower]
+	if !ok {
+		result = lower
+		for i := len(rules) - 1; i >= 0; i-- {
+			if rules[i].pattern.MatchString(lower) {
+				result = rules[i].pattern.ReplaceAllString(lower, rules[i].replacement)
+				break
+			}
+		}
+	}
+
+	// SKU becomes SKUs, Person becomes People
+	common := 0
+	for common < len(lower) && common < len(result) && lower[common] == result[common] {
+		common++
+	}
+	if common > 0 {
+		return word[:common] + result[common:]
+	}
+	if unicode.IsUpper(rune(word[0])) {
+		return titleCase(result)
+	}
+	return result
+}
+
+// splitWords splits PascalCase, camelCase, snake_case, kebab-case and
+// space separated names into words. Runs of capitals are kept together as
+// an acronym, so HTTPServer is HTTP and Server.
+func splitWords(name string) []string {
+	var words []string
+	for _, chunk := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(chunk)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, r := runes[i-1], runes[i]
+			next := rune(0)
+			if i+1 < len(runes) {
+				next = runes[i+1]
+			}
+			if unicode.IsUpper(r) && (!unicode.IsUpper(prev) || unicode.IsLower(next)) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// capitalize uppercases an acronym and the first letter of any other word
+func capitalize(word string) string {
+	lower := strings.ToLower(word)
+	if inflections.acronyms[lower] {
+		return strings.ToUpper(lower)
+	}
+	return titleCase(lower)
+}
+
+// toPascalCase converts a name to PascalCase, e.g. featured_image becomes
+// FeaturedImage, category_id becomes CategoryId and api_key becomes APIKey
+func toPascalCase(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+// toCamelCase converts a name to camelCase, e.g. BlogPost becomes blogPost
+func toCamelCase(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(strings.ToLower(words[0]))
+	for _, word := range words[1:] {
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+// toSnakeCase converts a name to snake_case, e.g. BlogPost becomes blog_post
+func toSnakeCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "_"))
+}
+
+// toKebabCase converts a name to kebab-case, e.g. BlogPost becomes blog-post
+func toKebabCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
+
+// humanize converts a name to a label, e.g. featured_image becomes
+// Featured image and category_id becomes Category ID
+func humanize(s string) string {
+	return titleCase(humanWords(s))
+}
+
+// humanWords converts a name to lowercase words, keeping acronyms and ID,
+// e.g. BlogPost becomes blog post and CategoryId becomes category ID
+func humanWords(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		if lower := strings.ToLower(word); inflections.acronyms[lower] || lower == "id" {
+			words[i] = strings.ToUpper(word)
+		} else {
+			words[i] = strings.ToLower(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// pluralize returns the plural of a name using the project's inflections
+func pluralize(name string) string {
+	return inflections.Pluralize(name)
+}
+
+// singularize returns the singular of a name using the project's inflections
+func singularize(name string) string {
+	return inflections.Singularize(name)
+}
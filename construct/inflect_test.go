@@ -0,0 +1,199 @@
+package construct
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInflections(t *testing.T) {
+	tests := []struct {
+		singular, plural string
+	}{
+		{"post", "posts"},
+		{"category", "categories"},
+		{"box", "boxes"},
+		{"status", "statuses"},
+		{"analysis", "analyses"},
+		{"knife", "knives"},
+		{"matrix", "matrices"},
+		{"quiz", "quizzes"},
+		{"octopus", "octopi"},
+		{"database", "databases"},
+		{"class", "classes"},
+		// Singular words ending in s
+		{"bonus", "bonuses"},
+		{"cactus", "cactuses"},
+		{"canvas", "canvases"},
+		{"atlas", "atlases"},
+		{"gas", "gases"},
+		{"lens", "lenses"},
+		{"iris", "irises"},
+		{"sms", "smses"},
+		{"criterion", "criteria"},
+		{"phenomenon", "phenomena"},
+		// Irregular
+		{"person", "people"},
+		{"child", "children"},
+		{"woman", "women"},
+		{"foot", "feet"},
+		{"goose", "geese"},
+		{"mouse", "mice"},
+		{"ox", "oxen"},
+		// Uncountable
+		{"equipment", "equipment"},
+		{"news", "news"},
+		{"series", "series"},
+		{"sheep", "sheep"},
+		{"metadata", "metadata"},
+		{"chassis", "chassis"},
+		// Names inflect their last word and keep its case
+		{"BlogPost", "BlogPosts"},
+		{"blog_category", "blog_categories"},
+		{"SalesPerson", "SalesPeople"},
+		{"Person", "People"},
+		{"SKU", "SKUs"},
+		{"UserInformation", "UserInformation"},
+		{"LoyaltyBonus", "LoyaltyBonuses"},
+		{"Sms", "Smses"},
+	}
+	for _, tt := range tests {
+		if got := pluralize(tt.singular); got != tt.plural {
+			t.Errorf("pluralize(%q) = %q, want %q", tt.singular, got, tt.plural)
+		}
+		if got := singularize(tt.plural); got != tt.singular {
+			t.Errorf("singularize(%q) = %q, want %q", tt.plural, got, tt.singular)
+		}
+	}
+
+	// Irregular plurals are kept
+	for _, word := range []string{"people", "children", "Women"} {
+		if got := pluralize(word); got != word {
+			t.Errorf("pluralize(%q) = %q, want it kept", word, got)
+		}
+	}
+}
+
+func TestResourceNamesNeedAPlural(t *testing.T) {
+	for _, name := range []string{"Bonus", "Canvas", "Lens", "Gas", "Atlas", "Cactus", "Iris", "Sms", "Class", "Criterion"} {
+		if _, err := NewTemplateData(name, nil); err != nil {
+			t.Errorf("NewTemplateData(%q) = %v, want it accepted", name, err)
+		}
+	}
+	for _, name := range []string{"Equipment", "Chassis", "News", "People"} {
+		if _, err := NewTemplateData(name, nil); err == nil {
+			t.Errorf("NewTemplateData(%q) succeeded, want an error for a name without a plural", name)
+		}
+	}
+}
+
+func TestCaseConversions(t *testing.T) {
+	tests := []struct {
+		name, pascal, camel, snake, kebab, human string
+	}{
+		{"blog_post", "BlogPost", "blogPost", "blog_post", "blog-post", "Blog post"},
+		{"BlogPost", "BlogPost", "blogPost", "blog_post", "blog-post", "Blog post"},
+		{"category_id", "CategoryId", "categoryId", "category_id", "category-id", "Category ID"},
+		{"external_uuid", "ExternalUUID", "externalUUID", "external_uuid", "external-uuid", "External UUID"},
+		{"HTTPServer", "HTTPServer", "httpServer", "http_server", "http-server", "HTTP server"},
+		{"api-key", "APIKey", "apiKey", "api_key", "api-key", "API key"},
+		{"featured image", "FeaturedImage", "featuredImage", "featured_image", "featured-image", "Featured image"},
+	}
+	for _, tt := range tests {
+		got := [5]string{toPascalCase(tt.name), toCamelCase(tt.name), toSnakeCase(tt.name), toKebabCase(tt.name), humanize(tt.name)}
+		if want := [5]string{tt.pascal, tt.camel, tt.snake, tt.kebab, tt.human}; got != want {
+			t.Errorf("%q converts to %q, want %q", tt.name, got, want)
+		}
+	}
+}
+
+func TestLoadInflections(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, inflectionsPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `irregular:
+  cactus: cacti
+uncountable:
+  - Moose
+acronyms:
+  - SKU
+plural:
+  - match: "(?i)(alumn)us$"
+    replace: "${1}i"
+singular:
+  - match: "(?i)(alumn)i$"
+    replace: "${1}us"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { inflections = defaultInflections() })
+
+	if err := loadInflections(root); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		singular, plural string
+	}{
+		{"cactus", "cacti"},
+		{"moose", "moose"},
+		{"alumnus", "alumni"},
+		{"GraduateAlumnus", "GraduateAlumni"},
+		{"person", "people"}, // The defaults still apply
+	} {
+		if got := pluralize(tt.singular); got != tt.plural {
+			t.Errorf("pluralize(%q) = %q, want %q", tt.singular, got, tt.plural)
+		}
+		if got := singularize(tt.plural); got != tt.singular {
+			t.Errorf("singularize(%q) = %q, want %q", tt.plural, got, tt.singular)
+		}
+	}
+	if got := toPascalCase("product_sku"); got != "ProductSKU" {
+		t.Errorf("toPascalCase(%q) = %q, want ProductSKU", "product_sku", got)
+	}
+
+	// Loading a project without inflections goes back to the defaults
+	if err := loadInflections(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if got := pluralize("moose"); got != "mooses" {
+		t.Errorf("pluralize(%q) = %q after loading the defaults, want mooses", "moose", got)
+	}
+}
+
+func TestLoadInflectionsErrors(t *testing.T) {
+	t.Cleanup(func() { inflections = defaultInflections() })
+	for _, content := range []string{
+		"irregular: [cactus]\n",
+		"plural:\n  - match: \"(\"\n    replace: x\n",
+		"singular:\n  - match: \"[\"\n    replace: x\n",
+	} {
+		root := t.TempDir()
+		path := filepath.Join(root, inflectionsPath)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := loadInflections(root); err == nil {
+			t.Errorf("loadInflections() with %q succeeded, want an error", content)
+		}
+	}
+}
+
+func TestIdFieldNames(t *testing.T) {
+	relation, err := parseField("category:belongs_to")
+	if err != nil {
+		t.Fatal(err)
+	}
+	column, err := parseField("category_id:uint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relation.FieldName != "CategoryId" || column.FieldName != relation.FieldName {
+		t.Errorf("category:belongs_to is %s and category_id:uint is %s, want both CategoryId", relation.FieldName, column.FieldName)
+	}
+}
@@ -1,9 +1,12 @@
 package construct
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/base-go/mamba"
+	"github.com/spf13/pflag"
 )
 
 const version = "1.0.0"
@@ -39,11 +42,25 @@ func init() {
 	// Disable default help flag to use Mamba's help system
 	rootCmd.Flags().BoolP("help", "h", false, "help for construct")
 
+	// Mamba parses every flag against the root command, which rejects flags
+	// that belong to subcommands. Let each subcommand parse its own instead.
+	rootCmd.DisableFlagParsing = true
+
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(devCmd)
 	rootCmd.AddCommand(buildCmd)
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(destroyCmd)
+	rootCmd.AddCommand(fieldAddCmd)
+	rootCmd.AddCommand(fieldRemoveCmd)
+	rootCmd.AddCommand(fieldRenameCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(templatesEjectCmd)
+	rootCmd.AddCommand(templatesDiffCmd)
+	rootCmd.AddCommand(clientGenCmd)
+	rootCmd.AddCommand(typesSyncCmd)
 
 	// Future commands:
 	// rootCmd.AddCommand(migrateCmd)
@@ -54,6 +71,30 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// parseFlags parses a subcommand's flags and returns the remaining arguments
+func parseFlags(cmd *mamba.Command, args []string) []string {
+	cmd.Flags().Usage = func() {} // Mamba renders help itself
+	if err := cmd.ParseFlags(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			cmd.Help()
+			os.Exit(0)
+		}
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	return cmd.Flags().Args()
+}
+
+// invokedName returns the name or alias the command was invoked with
+func invokedName(cmd *mamba.Command) string {
+	for _, arg := range os.Args[1:] {
+		if arg == cmd.Name() || cmd.HasAlias(arg) {
+			return arg
+		}
+	}
+	return cmd.Name()
+}
+
 func printBanner() {
 	banner := `
    ____                _                   _
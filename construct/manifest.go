@@ -0,0 +1,129 @@
+package construct
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// manifestPath records what the generator last wrote to each file
+const manifestPath = ".construct/manifest.json"
+
+// manifestBaseDir keeps a copy of the last generated output of each file,
+// which is the common ancestor when merging regenerated files
+const manifestBaseDir = ".construct/generated"
+
+// Manifest tracks generated files so they can be regenerated without losing
+// hand edits
+type Manifest struct {
+	Version string                   `json:"version"` // CLI version that last wrote the manifest
+	Files   map[string]ManifestEntry `json:"files"`   // Keyed by path relative to the project root
+}
+
+// ManifestEntry describes the last generated output of a file
+type ManifestEntry struct {
+	Template        string `json:"template"`
+	TemplateVersion string `json:"template_version"`
+	Hash            string `json:"hash"` // sha256 of the generated content
+}
+
+// loadManifest reads the project manifest, returning an empty one if the
+// project has none yet
+func loadManifest(root string) (*Manifest, error) {
+	manifest := &Manifest{Version: version, Files: map[string]ManifestEntry{}}
+
+	content, err := os.ReadFile(filepath.Join(root, manifestPath))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]ManifestEntry{}
+	}
+	return manifest, nil
+}
+
+// save writes the manifest back to the project
+func (m *Manifest) save(root string) error {
+	path := filepath.Join(root, manifestPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	m.Version = version
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// record stores the generated output of a file as the base for later merges
+func (m *Manifest) record(root string, f *generatedFile) error {
+	base := filepath.Join(root, manifestBaseDir, filepath.FromSlash(f.Rel))
+	if err := os.MkdirAll(filepath.Dir(base), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(base, f.Content, 0644); err != nil {
+		return err
+	}
+
+	m.Files[f.Rel] = ManifestEntry{
+		Template:        f.Template,
+		TemplateVersion: f.TemplateVersion,
+		Hash:            contentHash(f.Content),
+	}
+	return nil
+}
+
+// forget drops a file from the manifest along with its recorded output
+func (m *Manifest) forget(root, rel string) error {
+	delete(m.Files, rel)
+	base := filepath.Join(root, manifestBaseDir, filepath.FromSlash(rel))
+	if err := os.Remove(base); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	removeEmptyDirs(filepath.Dir(base), filepath.Join(root, manifestBaseDir))
+	return nil
+}
+
+// base returns the last generated output of a file, if it is known
+func (m *Manifest) base(root, rel string) ([]byte, bool) {
+	entry, ok := m.Files[rel]
+	if !ok {
+		return nil, false
+	}
+	content, err := os.ReadFile(filepath.Join(root, manifestBaseDir, filepath.FromSlash(rel)))
+	if err != nil || contentHash(content) != entry.Hash {
+		return nil, false
+	}
+	return content, true
+}
+
+// modified reports whether a file on disk differs from its last generated
+// output, i.e. whether it was edited by hand
+func (m *Manifest) modified(rel string, current []byte) bool {
+	entry, ok := m.Files[rel]
+	return !ok || contentHash(current) != entry.Hash
+}
+
+// contentHash fingerprints file content
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// templateVersion identifies a template by the CLI version and its content,
+// so a changed template is detectable even without a new release
+func templateVersion(content string) string {
+	return version + "+" + contentHash([]byte(content))[:12]
+}
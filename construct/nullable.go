@@ -0,0 +1,73 @@
+package construct
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// A trailing "?" makes a field nullable, and "=VALUE" after the type is
+// shorthand for the default modifier:
+//
+//	bio:text?             *string column, null when left empty
+//	rank:int=10           *int column that falls back to 10
+//	score:float?=0.5      nullable, 0.5 when the request leaves it out
+//
+// Nullable fields are pointers in Go and "| null" in TypeScript. Update
+// requests read them into a models.Nullable, which tells a field left out
+// of the request from one set to null. Numbers and booleans with a default
+// are pointers too, so that 0 and false are not replaced by the default.
+
+// setNullable makes the field nullable, sent as null when the form leaves
+// it empty
+func (f *TemplateField) setNullable() {
+	f.IsNullable = true
+	if !f.HasDefault {
+		f.InitialValue = "null"
+	}
+	if f.TypeScriptType == "number" || f.TypeScriptType == "boolean" {
+		f.SubmitValue += " ?? null"
+	} else {
+		f.SubmitValue += " || null"
+	}
+}
+
+// isPointer reports whether the Go model stores the field as a pointer
+func (f TemplateField) isPointer() bool {
+	return f.IsNullable || f.HasDefault && (f.TypeScriptType == "number" || f.TypeScriptType == "boolean")
+}
+
+// ModelType returns the type of the field in the model and its responses
+func (f BackendField) ModelType() string {
+	if f.IsPointer {
+		return "*" + f.Type
+	}
+	return f.Type
+}
+
+// SwaggerType returns the JSON type a Nullable field is documented as
+func (f BackendField) SwaggerType() string {
+	switch {
+	case f.Type == "bool":
+		return "boolean"
+	case strings.HasPrefix(f.Type, "float"):
+		return "number"
+	case strings.Contains(f.Type, "int"):
+		return "integer"
+	default:
+		return "string"
+	}
+}
+
+// nullableFile renders models/nullable.go, which the update requests of
+// every resource share, or returns nil when the fields have no nullable
+// ones. Like utils/dates.ts it is left in place when a resource is
+// destroyed.
+func nullableFile(root string, data *BackendTemplateData) (*generatedFile, error) {
+	for _, f := range data.Fields {
+		if f.IsNullable {
+			t := projectTemplate(root, filepath.Join(root, "api", "models", "nullable.go"), "base/nullable.tmpl")
+			return renderFileTemplate(root, t, data)
+		}
+	}
+	return nil, nil
+}
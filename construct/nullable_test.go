@@ -0,0 +1,153 @@
+package construct
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// testGeneratedModels runs tests against generated models: it writes the
+// files into api/models of a new module and runs go test there
+func testGeneratedModels(t *testing.T, files ...*generatedFile) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("builds generated code")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.25\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "api", "models")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(f.Path)), f.Content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(goTool, "test", "./api/models")
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go test of the generated models failed: %v\n%s", err, out)
+	}
+}
+
+// backendField parses a field definition into the field the Vue templates
+// see and the one the Go templates see
+func backendField(t *testing.T, arg string) (TemplateField, BackendField) {
+	t.Helper()
+	f, err := parseField(arg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, NewBackendTemplateData("example.com/app", &TemplateData{Fields: []TemplateField{f}}).Fields[0]
+}
+
+func TestNullableFields(t *testing.T) {
+	tests := []struct {
+		arg                string
+		nullable, pointer  bool
+		modelType, swagger string
+		initial, submit    string
+	}{
+		{"bio:text?", true, true, "*string", "string", "null", "event.data.bio || null"},
+		{"views:uint64?", true, true, "*uint64", "integer", "null", "event.data.views ?? null"},
+		{"score:float?=0.5", true, true, "*float64", "number", "0.5", "event.data.score ?? null"},
+		{"price:money?", true, true, "*Decimal", "string", "null", "event.data.price || null"},
+		// Numbers and booleans with a default are pointers, so 0 and false
+		// are not replaced by it
+		{"rank:int=10", false, true, "*int", "integer", "10", "event.data.rank"},
+		{"active:bool=true", false, true, "*bool", "boolean", "true", "event.data.active"},
+		{"active:bool", false, false, "bool", "boolean", "false", "event.data.active"},
+		{"title:string=x", false, false, "string", "string", "'x'", "event.data.title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			f, b := backendField(t, tt.arg)
+			if b.IsNullable != tt.nullable || b.IsPointer != tt.pointer {
+				t.Errorf("nullable=%v pointer=%v, want %v %v", b.IsNullable, b.IsPointer, tt.nullable, tt.pointer)
+			}
+			if b.ModelType() != tt.modelType || b.SwaggerType() != tt.swagger {
+				t.Errorf("model type %s documented as %s, want %s documented as %s", b.ModelType(), b.SwaggerType(), tt.modelType, tt.swagger)
+			}
+			if f.InitialValue != tt.initial || f.SubmitValue != tt.submit {
+				t.Errorf("form starts at %q and submits %q, want %q and %q", f.InitialValue, f.SubmitValue, tt.initial, tt.submit)
+			}
+		})
+	}
+}
+
+// nullableTest checks the three states of a Nullable in the generated models
+const nullableTest = `package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullable(t *testing.T) {
+	tests := []struct {
+		body  string
+		set   bool
+		value string // "" for nil
+	}{
+		{"{}", false, ""},
+		{` + "`" + `{"bio":null}` + "`" + `, true, ""},
+		{` + "`" + `{"bio":"hi"}` + "`" + `, true, "hi"},
+		{` + "`" + `{"bio":""}` + "`" + `, true, ""},
+	}
+	for _, tt := range tests {
+		var req struct {
+			Bio Nullable[string] ` + "`" + `json:"bio"` + "`" + `
+		}
+		if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+			t.Fatal(err)
+		}
+		value := ""
+		if req.Bio.Value != nil {
+			value = *req.Bio.Value
+		}
+		if req.Bio.Set != tt.set || value != tt.value || tt.body == ` + "`" + `{"bio":null}` + "`" + ` && req.Bio.Value != nil {
+			t.Errorf("%s reads as set=%v value=%v, want set=%v value=%q", tt.body, req.Bio.Set, req.Bio.Value, tt.set, tt.value)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(` + "`" + `{"bio":1}` + "`" + `), &struct{ Bio Nullable[string] }{}); err == nil {
+		t.Error("a number reads into Nullable[string]")
+	}
+	out, err := json.Marshal(struct{ Bio Nullable[string] }{})
+	if err != nil || string(out) != ` + "`" + `{"Bio":null}` + "`" + ` {
+		t.Errorf("an unset Nullable marshals to %s, %v, want null", out, err)
+	}
+}
+`
+
+// builtinFile renders a file from its built-in template, leaving out any
+// overrides of the user running the tests
+func builtinFile(t *testing.T, path, name string, data interface{}) *generatedFile {
+	t.Helper()
+	tmpl := fileTemplate{path: path, name: name, template: builtinTemplates()[name], source: "built-in/" + name}
+	file, err := renderFileTemplate(t.TempDir(), tmpl, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestGeneratedNullable(t *testing.T) {
+	data, err := NewTemplateData("Profile", []string{"bio:text?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := builtinFile(t, "nullable.go", "base/nullable.tmpl", NewBackendTemplateData("example.com/app", data))
+	testGeneratedModels(t, file, &generatedFile{Path: "nullable_test.go", Content: []byte(nullableTest)})
+}
@@ -0,0 +1,310 @@
+package construct
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/base-go/mamba"
+)
+
+// templateOverrideDir holds a project's copies of the built-in templates,
+// which take precedence over ~/.base/templates and the built-ins
+const templateOverrideDir = ".construct/templates"
+
+var templatesCmd = &mamba.Command{
+	Use:   "templates",
+	Short: "List the code generation templates",
+	Long: `List the templates construct g renders and where each one is loaded from.
+
+A template is looked up in .construct/templates/<name> in the project, then
+in ~/.base/templates/<name>, before the one built into the CLI is used.
+
+Examples:
+  construct templates
+  construct templates:eject frontend/index.vue
+  construct templates:diff`,
+	Run: func(cmd *mamba.Command, args []string) {
+		parseFlags(cmd, args)
+		printBanner()
+
+		root, err := findProjectRoot()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, name := range templateNames() {
+			_, source := lookupTemplate(root, name)
+			fmt.Printf("  %-26s  %s\n", name, source)
+		}
+	},
+}
+
+var templatesEjectCmd = &mamba.Command{
+	Use:   "templates:eject [name...]",
+	Short: "Copy built-in templates into the project for editing",
+	Long: `Copy built-in templates to .construct/templates so they can be edited.
+
+Without a name every template is ejected. A name may be a template such as
+frontend/index.vue, its file name with or without the extension, or a
+directory such as frontend.
+
+Examples:
+  construct templates:eject                     # All templates
+  construct templates:eject index.vue store.ts
+  construct templates:eject base --global       # Into ~/.base/templates`,
+	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+		force, _ := cmd.Flags().GetBool("force")
+		global, _ := cmd.Flags().GetBool("global")
+		runTemplatesEject(args, force, global)
+	},
+}
+
+var templatesDiffCmd = &mamba.Command{
+	Use:   "templates:diff [name...]",
+	Short: "Show how ejected templates differ from the built-ins",
+	Long: `Show a unified diff between each ejected template and the template
+built into this version of the CLI, to spot changes to carry over after an
+upgrade.
+
+Examples:
+  construct templates:diff
+  construct templates:diff frontend/index.vue`,
+	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+		runTemplatesDiff(args)
+	},
+}
+
+func init() {
+	templatesEjectCmd.Flags().Bool("force", false, "overwrite templates that were already ejected")
+	templatesEjectCmd.Flags().Bool("global", false, "eject into ~/.base/templates for every project")
+}
+
+// userTemplateDir holds the current user's template overrides
+func userTemplateDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".base", "templates")
+}
+
+// templateDirs lists the directories searched for overrides, in order
+func templateDirs(root string) []string {
+	dirs := []string{filepath.Join(root, templateOverrideDir)}
+	if dir := userTemplateDir(); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// lookupTemplate returns a template and where it was found, preferring the
+// project's override, then the user's, then the built-in template
+func lookupTemplate(root, name string) (string, string) {
+	for _, dir := range templateDirs(root) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if content, err := os.ReadFile(path); err == nil {
+			return string(content), displayPath(root, path)
+		}
+	}
+	return builtinTemplates()[name], "built-in"
+}
+
+// projectTemplate pairs an output path with the template the project
+// renders it from
+func projectTemplate(root, path, name string) fileTemplate {
+	content, source := lookupTemplate(root, name)
+	if source == "built-in" {
+		source = "built-in/" + name
+	}
+	return fileTemplate{path: path, name: name, template: content, source: source}
+}
+
+// templateNames lists the built-in templates
+func templateNames() []string {
+	var names []string
+	for name := range builtinTemplates() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matchTemplates resolves template names, file names or directories to
+// template names. No arguments match every template.
+func matchTemplates(args []string) ([]string, error) {
+	all := templateNames()
+	if len(args) == 0 {
+		return all, nil
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, arg := range args {
+		arg = strings.Trim(filepath.ToSlash(arg), "/")
+		var matched []string
+		for _, name := range all {
+			base := filepath.Base(name)
+			if name == arg || strings.HasPrefix(name, arg+"/") || base == arg || strings.TrimSuffix(base, filepath.Ext(base)) == arg {
+				matched = append(matched, name)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("unknown template %q; run construct templates to list them", arg)
+		}
+		for _, name := range matched {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// displayPath shows a path relative to the project, or with ~ for the home
+// directory
+func displayPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, home+string(os.PathSeparator)) {
+		return "~" + filepath.ToSlash(strings.TrimPrefix(path, home))
+	}
+	return path
+}
+
+func runTemplatesEject(args []string, force, global bool) {
+	printBanner()
+
+	root, err := findProjectRoot()
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	names, err := matchTemplates(args)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	dir := filepath.Join(root, templateOverrideDir)
+	if global {
+		if dir = userTemplateDir(); dir == "" {
+			fmt.Println("❌ Error: could not determine the home directory")
+			os.Exit(1)
+		}
+	}
+
+	ejected, err := EjectTemplates(dir, names, force)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, name := range names {
+		path := displayPath(root, filepath.Join(dir, filepath.FromSlash(name)))
+		if ejected[name] {
+			fmt.Printf("  ✓ Ejected %s\n", path)
+		} else {
+			fmt.Printf("  • Skipped %s (already ejected, use --force to overwrite)\n", path)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("🎉 Edit the ejected templates; construct g will use them from now on")
+}
+
+// EjectTemplates copies built-in templates into dir, keeping copies that
+// already exist unless forced. It reports which templates were written.
+func EjectTemplates(dir string, names []string, force bool) (map[string]bool, error) {
+	builtin := builtinTemplates()
+	ejected := map[string]bool{}
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if fileExists(path) && !force {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return ejected, err
+		}
+		if err := os.WriteFile(path, []byte(builtin[name]), 0644); err != nil {
+			return ejected, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		ejected[name] = true
+	}
+	return ejected, nil
+}
+
+func runTemplatesDiff(args []string) {
+	printBanner()
+
+	root, err := findProjectRoot()
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	names, err := matchTemplates(args)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	builtin := builtinTemplates()
+	found := 0
+	for _, dir := range templateDirs(root) {
+		for _, name := range names {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			content, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			found++
+
+			shown := displayPath(root, path)
+			if string(content) == builtin[name] {
+				fmt.Printf("  = %s (same as built-in)\n", shown)
+				continue
+			}
+			fmt.Printf("  ~ %s\n", shown)
+			fmt.Print(unifiedDiff("built-in/"+name, shown, builtin[name], string(content)))
+		}
+
+		// Files the generator would never read are most likely misnamed
+		if len(args) == 0 {
+			for _, path := range unknownTemplates(dir) {
+				fmt.Printf("  ⚠️  %s is not a known template and is ignored\n", displayPath(root, path))
+			}
+		}
+	}
+
+	if found == 0 {
+		fmt.Println("No ejected templates; run construct templates:eject to create some")
+	}
+}
+
+// unknownTemplates lists the files in an override directory that do not
+// correspond to a built-in template or add a partial
+func unknownTemplates(dir string) []string {
+	builtin := builtinTemplates()
+	var unknown []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		rel = filepath.ToSlash(rel)
+		if _, ok := builtin[rel]; !ok && !(filepath.Dir(rel) == partialsDir && filepath.Ext(rel) == ".tmpl") {
+			unknown = append(unknown, path)
+		}
+		return nil
+	})
+	return unknown
+}
@@ -0,0 +1,111 @@
+package construct
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMatchTemplates(t *testing.T) {
+	tests := []struct {
+		args  []string
+		names []string // nil when the arguments match nothing
+	}{
+		{[]string{"base/model.tmpl"}, []string{"base/model.tmpl"}},
+		{[]string{"model.tmpl"}, []string{"base/model.tmpl"}},
+		{[]string{"model"}, []string{"base/model.tmpl"}},
+		{[]string{"index.vue", "store"}, []string{"frontend/index.vue", "frontend/store.ts"}},
+		{[]string{"partials/"}, []string{"partials/form_field.tmpl", "partials/table_cell.tmpl"}},
+		{[]string{"model", "base/model.tmpl"}, []string{"base/model.tmpl"}},
+		{[]string{"modle"}, nil},
+	}
+	for _, tt := range tests {
+		names, err := matchTemplates(tt.args)
+		if tt.names == nil {
+			if err == nil {
+				t.Errorf("matchTemplates(%q) = %q, want an unknown template error", tt.args, names)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("matchTemplates(%q) = %q, %v, want %q", tt.args, names, err, tt.names)
+		}
+	}
+
+	if names, err := matchTemplates(nil); err != nil || !reflect.DeepEqual(names, templateNames()) {
+		t.Errorf("matchTemplates() = %q, %v, want every template", names, err)
+	}
+}
+
+func TestLookupTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	root := t.TempDir()
+	override := func(dir, name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	override(filepath.Join(root, templateOverrideDir), "base/model.tmpl", "project model")
+	override(userTemplateDir(), "base/model.tmpl", "user model")
+	override(userTemplateDir(), "base/service.tmpl", "user service")
+
+	tests := []struct {
+		name, content, source string
+	}{
+		{"base/model.tmpl", "project model", ".construct/templates/base/model.tmpl"},
+		{"base/service.tmpl", "user service", "~/.base/templates/base/service.tmpl"},
+		{"base/controller.tmpl", builtinTemplates()["base/controller.tmpl"], "built-in"},
+	}
+	for _, tt := range tests {
+		if content, source := lookupTemplate(root, tt.name); content != tt.content || source != tt.source {
+			t.Errorf("lookupTemplate(%s) = %.20q from %s, want %.20q from %s", tt.name, content, source, tt.content, tt.source)
+		}
+	}
+}
+
+func TestEjectTemplates(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"base/model.tmpl", "frontend/index.vue"}
+	if ejected, err := EjectTemplates(dir, names, false); err != nil || len(ejected) != 2 {
+		t.Fatalf("EjectTemplates() = %v, %v, want both ejected", ejected, err)
+	}
+
+	model := filepath.Join(dir, "base", "model.tmpl")
+	if err := os.WriteFile(model, []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		force   bool
+		ejected bool
+		content string
+	}{
+		{false, false, "edited"},
+		{true, true, builtinTemplates()["base/model.tmpl"]},
+	} {
+		ejected, err := EjectTemplates(dir, names[:1], tt.force)
+		if err != nil || ejected[names[0]] != tt.ejected {
+			t.Errorf("EjectTemplates(force=%v) = %v, %v, want ejected %v", tt.force, ejected, err, tt.ejected)
+		}
+		if content, _ := os.ReadFile(model); string(content) != tt.content {
+			t.Errorf("after EjectTemplates(force=%v) the copy holds %.20q, want %.20q", tt.force, content, tt.content)
+		}
+	}
+
+	if unknown := unknownTemplates(dir); len(unknown) != 0 {
+		t.Errorf("unknownTemplates() = %q for ejected templates, want none", unknown)
+	}
+	stray := filepath.Join(dir, "base", "modal.tmpl")
+	if err := os.WriteFile(stray, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if unknown := unknownTemplates(dir); !reflect.DeepEqual(unknown, []string{stray}) {
+		t.Errorf("unknownTemplates() = %q, want %q", unknown, stray)
+	}
+}
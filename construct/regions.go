@@ -0,0 +1,216 @@
+package construct
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Frontend templates mark the per-field parts of a file with
+//
+//	// construct:fields <region>
+//	...
+//	// construct:end
+//
+// or the <!-- --> equivalents in Vue markup, so fields can be added, removed
+// and renamed in files that were edited since they were generated. Inside a
+// region each field is a block starting at the indentation of the marker.
+
+var (
+	regionStartPattern = regexp.MustCompile(`^\s*(?://|<!--)\s*construct:fields\s+(\w+)\s*(?:-->)?\s*$`)
+	regionEndPattern   = regexp.MustCompile(`^\s*(?://|<!--)\s*construct:end\s*(?:-->)?\s*$`)
+)
+
+// fieldRegion is a marked region of a file
+type fieldRegion struct {
+	name       string
+	start, end int // Line indexes of the start and end markers
+	indent     string
+}
+
+// findRegions returns the marked regions in lines, in order
+func findRegions(lines []string) []fieldRegion {
+	var regions []fieldRegion
+	for i := 0; i < len(lines); i++ {
+		m := regionStartPattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		for j := i + 1; j < len(lines); j++ {
+			if regionEndPattern.MatchString(lines[j]) {
+				indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+				regions = append(regions, fieldRegion{name: m[1], start: i, end: j, indent: indent})
+				i = j
+				break
+			}
+		}
+	}
+	return regions
+}
+
+// regionBlocks splits a region into per-field blocks of [start, end) line
+// indexes. A block starts at a line at the region's indentation that does
+// not close an earlier one; blank lines belong to the block before them.
+func regionBlocks(lines []string, r fieldRegion) [][2]int {
+	var blocks [][2]int
+	for i := r.start + 1; i < r.end; i++ {
+		line := lines[i]
+		rest := strings.TrimPrefix(line, r.indent)
+		startsBlock := strings.TrimSpace(line) != "" &&
+			len(rest) == len(line)-len(r.indent) &&
+			!strings.HasPrefix(rest, " ") && !strings.HasPrefix(rest, "\t") &&
+			!strings.HasPrefix(rest, "</") && !strings.ContainsAny(rest[:1], "})]")
+		if startsBlock || len(blocks) == 0 {
+			blocks = append(blocks, [2]int{i, i + 1})
+			continue
+		}
+		blocks[len(blocks)-1][1] = i + 1
+	}
+	return blocks
+}
+
+// fieldTokenPattern matches a field name used as an identifier, key or
+// string in a line, alone or as the start of one of its filter parameters
+// such as name_contains, but not as an attribute such as name="..."
+func fieldTokenPattern(name string) *regexp.Regexp {
+	suffixes := `(?:` + strings.Join(filterSuffixes, "|") + `)?`
+	return regexp.MustCompile(`(^|[^\w-])` + regexp.QuoteMeta(name) + `(` + suffixes + `(?:[^\w=]|$))`)
+}
+
+// findFieldBlock finds the lines of a region that belong to a field, given
+// the first lines of the blocks the template renders for it. A field such as
+// a relation may render several blocks, which are expected to follow each
+// other. The block starting with the first rendered line is preferred;
+// otherwise the first block whose first line mentions the field is used.
+func findFieldBlock(lines []string, r fieldRegion, name string, rendered []string) ([2]int, bool) {
+	blocks := regionBlocks(lines, r)
+	span := func(i int) [2]int {
+		last := min(i+max(len(rendered), 1), len(blocks)) - 1
+		return [2]int{blocks[i][0], blocks[last][1]}
+	}
+	for i, b := range blocks {
+		if len(rendered) > 0 && strings.TrimSpace(lines[b[0]]) == strings.TrimSpace(rendered[0]) {
+			return span(i), true
+		}
+	}
+	pattern := fieldTokenPattern(name)
+	for i, b := range blocks {
+		if pattern.MatchString(lines[b[0]]) {
+			return span(i), true
+		}
+	}
+	return [2]int{}, false
+}
+
+// renderedRegions maps each region of a rendered template to its content
+// lines, skipping regions the fields add nothing to
+func renderedRegions(rendered string) map[string][]string {
+	lines := splitLines(rendered)
+	content := map[string][]string{}
+	for _, r := range findRegions(lines) {
+		if r.end > r.start+1 {
+			content[r.name] = lines[r.start+1 : r.end]
+		}
+	}
+	return content
+}
+
+// addRegionFields appends the content of each region of rendered, the
+// template rendered with just the new fields, to the same region of current.
+// It returns the regions that had content to add but are missing.
+func addRegionFields(current, rendered string) (string, []string) {
+	lines := splitLines(current)
+	regions := findRegions(lines)
+
+	var missing []string
+	add := renderedRegions(rendered)
+	for i := len(regions) - 1; i >= 0; i-- {
+		r := regions[i]
+		content, ok := add[r.name]
+		if !ok {
+			continue
+		}
+		delete(add, r.name)
+		lines = append(lines[:r.end], append(append([]string{}, content...), lines[r.end:]...)...)
+	}
+	for _, r := range findRegions(splitLines(rendered)) {
+		if _, ok := add[r.name]; ok {
+			missing = append(missing, r.name)
+		}
+	}
+
+	return joinLines(lines, current), missing
+}
+
+// removeRegionField removes the block of a field from every region. It
+// reports whether anything was removed.
+func removeRegionField(current, rendered, name string) (string, bool) {
+	lines := splitLines(current)
+	first := renderedBlocks(rendered)
+
+	removed := false
+	regions := findRegions(lines)
+	for i := len(regions) - 1; i >= 0; i-- {
+		r := regions[i]
+		if b, ok := findFieldBlock(lines, r, name, first[r.name]); ok {
+			lines = append(lines[:b[0]], lines[b[1]:]...)
+			removed = true
+		}
+	}
+	return joinLines(lines, current), removed
+}
+
+// renameRegionField renames a field, and its label, in its block of every
+// region. It reports whether anything was renamed.
+func renameRegionField(current, rendered, oldName, newName, oldLabel, newLabel string) (string, bool) {
+	lines := splitLines(current)
+	first := renderedBlocks(rendered)
+	pattern := fieldTokenPattern(oldName)
+	labels := strings.NewReplacer(
+		`"`+oldLabel+`"`, `"`+newLabel+`"`,
+		`'`+oldLabel+`'`, `'`+newLabel+`'`,
+		`'`+oldLabel+` `, `'`+newLabel+` `, // Validation messages
+	)
+
+	renamed := false
+	for _, r := range findRegions(lines) {
+		b, ok := findFieldBlock(lines, r, oldName, first[r.name])
+		if !ok {
+			continue
+		}
+		for i := b[0]; i < b[1]; i++ {
+			// Matches share separators, so a second pass catches neighbours
+			line := lines[i]
+			for pass := 0; pass < 2; pass++ {
+				line = pattern.ReplaceAllString(line, "${1}"+newName+"${2}")
+			}
+			line = labels.Replace(line)
+			if line != lines[i] {
+				lines[i] = line
+				renamed = true
+			}
+		}
+	}
+	return joinLines(lines, current), renamed
+}
+
+// renderedBlocks maps each region of a rendered template to the first lines
+// of its blocks
+func renderedBlocks(rendered string) map[string][]string {
+	lines := splitLines(rendered)
+	first := map[string][]string{}
+	for _, r := range findRegions(lines) {
+		for _, b := range regionBlocks(lines, r) {
+			first[r.name] = append(first[r.name], lines[b[0]])
+		}
+	}
+	return first
+}
+
+// joinLines joins lines, keeping the final newline of original if it had one
+func joinLines(lines []string, original string) string {
+	content := strings.Join(lines, "\n")
+	if strings.HasSuffix(original, "\n") {
+		content += "\n"
+	}
+	return content
+}
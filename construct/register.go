@@ -0,0 +1,291 @@
+package construct
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// moduleRegistry describes where modules are registered in api/init.go:
+//
+//	func InitializeModules(deps module.Dependencies) map[string]module.Module {
+//		modules := make(map[string]module.Module)
+//		modules["posts"] = posts.Init(deps)
+//		return modules
+//	}
+type moduleRegistry struct {
+	*goSource
+	fn       *ast.FuncDecl
+	mapName  string          // Name of the returned map, e.g. "modules"
+	depsName string          // Name of the dependencies parameter, e.g. "deps"
+	ret      *ast.ReturnStmt // Final return statement of the function
+}
+
+// registeredSource returns the content of api/init.go with the import and
+// modules["name"] = name.Init(deps) registration of a generated module
+// added, without writing it. The content is unchanged if the module is
+// already registered.
+func registeredSource(initPath, modulePath, moduleName string) ([]byte, error) {
+	reg, err := loadModuleRegistry(initPath)
+	if err != nil {
+		return nil, err
+	}
+	return reg.register(modulePath, moduleName)
+}
+
+// register returns the source with the module's import and registration added
+func (r *moduleRegistry) register(modulePath, moduleName string) ([]byte, error) {
+	if r.findRegistration(moduleName) != nil {
+		return r.src, nil
+	}
+
+	importPath := modulePath + "/api/" + moduleName
+	var edits []sourceEdit
+
+	pkgName, imported := r.importName(importPath)
+	if !imported {
+		pkgName = moduleName
+		if r.nameInUse(pkgName) {
+			pkgName = moduleName + "module"
+		}
+		edits = append(edits, r.addImport(importPath, pkgName, moduleName))
+	}
+
+	// Register after the existing modules, right before the return
+	stmt := fmt.Sprintf("// %s module\n%s[%q] = %s.Init(%s)\n\n",
+		humanize(moduleName), r.mapName, moduleName, pkgName, r.depsName)
+	offset := r.lineStart(r.offset(r.ret.Pos()))
+	if prev := r.src[r.lineStart(max(offset-1, 0)):offset]; len(bytes.TrimSpace(prev)) > 0 {
+		stmt = "\n" + stmt // Keep registrations visually separate
+	}
+	edits = append(edits, sourceEdit{start: offset, end: offset, text: stmt})
+
+	return r.render(edits)
+}
+
+// UnregisterModule removes the registration of a module from api/init.go,
+// along with its import once nothing else refers to it. It reports false if
+// the module was not registered.
+func UnregisterModule(initPath, modulePath, moduleName string) (bool, error) {
+	reg, err := loadModuleRegistry(initPath)
+	if err != nil {
+		return false, err
+	}
+
+	stmt := reg.findRegistration(moduleName)
+	if stmt == nil {
+		return false, nil
+	}
+
+	// Remove the statement together with a "// Name module" comment above it
+	start := reg.lineStart(reg.offset(stmt.Pos()))
+	for _, group := range reg.file.Comments {
+		if reg.fset.Position(group.End()).Line == reg.fset.Position(stmt.Pos()).Line-1 && isModuleComment(group, moduleName) {
+			start = reg.lineStart(reg.offset(group.Pos()))
+		}
+	}
+	end := reg.lineEnd(reg.offset(stmt.End()))
+
+	// Drop the blank line that separated it from the next statement
+	if next := reg.lineEnd(end); end < len(reg.src) && len(bytes.TrimSpace(reg.src[end:next])) == 0 {
+		end = next
+	}
+
+	if err := reg.write([]sourceEdit{{start: start, end: end}}); err != nil {
+		return false, err
+	}
+
+	// Re-read the file to drop the import if it is no longer referenced
+	reg, err = loadModuleRegistry(initPath)
+	if err != nil {
+		return true, err
+	}
+
+	importPath := modulePath + "/api/" + moduleName
+	pkgName, imported := reg.importName(importPath)
+	if !imported || reg.referencesPackage(pkgName) {
+		return true, nil
+	}
+
+	for _, decl := range reg.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			imp := spec.(*ast.ImportSpec)
+			if strings.Trim(imp.Path.Value, `"`) != importPath {
+				continue
+			}
+			node := ast.Node(imp)
+			if len(gen.Specs) == 1 {
+				node = gen
+			}
+			edit := sourceEdit{start: reg.lineStart(reg.offset(node.Pos())), end: reg.lineEnd(reg.offset(node.End()))}
+			return true, reg.write([]sourceEdit{edit})
+		}
+	}
+
+	return true, nil
+}
+
+// loadModuleRegistry parses api/init.go and locates the registration function
+func loadModuleRegistry(path string) (*moduleRegistry, error) {
+	source, err := loadGoSource(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reg := &moduleRegistry{goSource: source}
+
+	// Position of a function returning a map in a way modules cannot be
+	// registered in, reported when no function fits
+	unsupported := token.NoPos
+	for _, decl := range reg.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+			continue
+		}
+		if _, ok := fn.Type.Results.List[0].Type.(*ast.MapType); !ok {
+			continue
+		}
+
+		// The function must end by returning the map it fills
+		var ret *ast.ReturnStmt
+		if len(fn.Body.List) > 0 {
+			ret, _ = fn.Body.List[len(fn.Body.List)-1].(*ast.ReturnStmt)
+		}
+		if ret == nil {
+			if !unsupported.IsValid() {
+				unsupported = fn.Body.Rbrace
+			}
+			continue
+		}
+		var ident *ast.Ident
+		if len(ret.Results) == 1 {
+			ident, _ = ret.Results[0].(*ast.Ident)
+		}
+		if ident == nil {
+			if !unsupported.IsValid() {
+				unsupported = ret.Pos()
+			}
+			continue
+		}
+
+		reg.fn = fn
+		reg.ret = ret
+		reg.mapName = ident.Name
+		break
+	}
+
+	if reg.fn == nil && unsupported.IsValid() {
+		return nil, reg.errorf(unsupported, "the module registration function must end by returning the map it fills, e.g. return modules")
+	}
+	if reg.fn == nil {
+		return nil, reg.errorf(reg.file.Package, "could not find the module registration function; expected a function returning a map, e.g.\n\n"+
+			"  func InitializeModules(deps module.Dependencies) map[string]module.Module {\n"+
+			"      modules := make(map[string]module.Module)\n"+
+			"      return modules\n"+
+			"  }")
+	}
+
+	for _, field := range reg.fn.Type.Params.List {
+		if sel, ok := field.Type.(*ast.SelectorExpr); ok && sel.Sel.Name == "Dependencies" && len(field.Names) > 0 {
+			reg.depsName = field.Names[0].Name
+		}
+	}
+	if reg.depsName == "" {
+		return nil, reg.errorf(reg.fn.Pos(), "%s has no module.Dependencies parameter to pass to Init", reg.fn.Name.Name)
+	}
+
+	return reg, nil
+}
+
+// findRegistration returns the modules["name"] = ... statement, if any
+func (r *moduleRegistry) findRegistration(moduleName string) *ast.AssignStmt {
+	for _, stmt := range r.fn.Body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 {
+			continue
+		}
+		index, ok := assign.Lhs[0].(*ast.IndexExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := index.X.(*ast.Ident); !ok || ident.Name != r.mapName {
+			continue
+		}
+		if lit, ok := index.Index.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if key, err := strconv.Unquote(lit.Value); err == nil && key == moduleName {
+				return assign
+			}
+		}
+	}
+	return nil
+}
+
+// renamedSource returns the content of api/init.go with a module's import
+// and registration renamed in place, without writing it
+func renamedSource(initPath, modulePath, oldName, newName string) ([]byte, error) {
+	reg, err := loadModuleRegistry(initPath)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := reg.findRegistration(oldName)
+	if stmt == nil {
+		return reg.src, nil
+	}
+
+	key := stmt.Lhs[0].(*ast.IndexExpr).Index
+	edits := []sourceEdit{{start: reg.offset(key.Pos()), end: reg.offset(key.End()), text: strconv.Quote(newName)}}
+
+	for _, group := range reg.file.Comments {
+		if reg.fset.Position(group.End()).Line == reg.fset.Position(stmt.Pos()).Line-1 && isModuleComment(group, oldName) {
+			edits = append(edits, sourceEdit{
+				start: reg.offset(group.Pos()),
+				end:   reg.offset(group.End()),
+				text:  "// " + humanize(newName) + " module",
+			})
+		}
+	}
+
+	oldPath, newPath := modulePath+"/api/"+oldName, modulePath+"/api/"+newName
+	for _, imp := range reg.file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != oldPath {
+			continue
+		}
+		edits = append(edits, sourceEdit{start: reg.offset(imp.Path.Pos()), end: reg.offset(imp.Path.End()), text: strconv.Quote(newPath)})
+
+		// A named import keeps its name; otherwise references follow the
+		// new package name
+		if imp.Name != nil {
+			continue
+		}
+		pkgName := newName
+		if reg.nameInUse(pkgName) {
+			pkgName = newName + "module"
+			edits = append(edits, sourceEdit{start: reg.offset(imp.Path.Pos()), end: reg.offset(imp.Path.Pos()), text: pkgName + " "})
+		}
+		ast.Inspect(reg.file, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == oldName {
+					edits = append(edits, sourceEdit{start: reg.offset(ident.Pos()), end: reg.offset(ident.End()), text: pkgName})
+				}
+			}
+			return true
+		})
+	}
+
+	return reg.render(edits)
+}
+
+// isModuleComment reports whether a comment is the "// Blog posts module"
+// line written above a registration
+func isModuleComment(group *ast.CommentGroup, moduleName string) bool {
+	text := strings.TrimSpace(group.Text())
+	return strings.EqualFold(text, humanize(moduleName)+" module") || strings.EqualFold(text, moduleName+" module")
+}
@@ -0,0 +1,244 @@
+package construct
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const initSource = `package api
+
+import (
+	"example.com/app/api/posts"
+	"example.com/app/core/module"
+)
+
+// InitializeModules registers all application modules
+func InitializeModules(deps module.Dependencies) map[string]module.Module {
+	modules := make(map[string]module.Module)
+
+	// Posts module
+	modules["posts"] = posts.Init(deps)
+
+	return modules
+}
+`
+
+const initSourceWithBlogPosts = `package api
+
+import (
+	"example.com/app/api/blog_posts"
+	"example.com/app/api/posts"
+	"example.com/app/core/module"
+)
+
+// InitializeModules registers all application modules
+func InitializeModules(deps module.Dependencies) map[string]module.Module {
+	modules := make(map[string]module.Module)
+
+	// Posts module
+	modules["posts"] = posts.Init(deps)
+
+	// Blog posts module
+	modules["blog_posts"] = blog_posts.Init(deps)
+
+	return modules
+}
+`
+
+// writeInit writes an api/init.go with content and returns its path
+func writeInit(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "init.go")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// checkInit fails the test unless the file at path holds want, formatted
+// by gofmt
+func checkInit(t *testing.T, path, want string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != want {
+		t.Errorf("init.go =\n%s\nwant\n%s", content, want)
+	}
+	if formatted, err := format.Source(content); err != nil || string(formatted) != string(content) {
+		t.Errorf("init.go is not gofmt'd: %v", err)
+	}
+}
+
+// registerInit registers a module in the api/init.go at path the way
+// generate does, writing the registered source back
+func registerInit(t *testing.T, path, moduleName string) {
+	t.Helper()
+	content, err := registeredSource(path, "example.com/app", moduleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisteredSource(t *testing.T) {
+	path := writeInit(t, initSource)
+
+	registerInit(t, path, "blog_posts")
+	checkInit(t, path, initSourceWithBlogPosts)
+
+	// Registering again leaves the file as it is
+	registerInit(t, path, "blog_posts")
+	checkInit(t, path, initSourceWithBlogPosts)
+}
+
+func TestUnregisterModule(t *testing.T) {
+	path := writeInit(t, initSourceWithBlogPosts)
+
+	unregistered, err := UnregisterModule(path, "example.com/app", "blog_posts")
+	if err != nil || !unregistered {
+		t.Fatalf("UnregisterModule() = %v, %v, want true", unregistered, err)
+	}
+	checkInit(t, path, initSource)
+
+	unregistered, err = UnregisterModule(path, "example.com/app", "blog_posts")
+	if err != nil || unregistered {
+		t.Fatalf("UnregisterModule() again = %v, %v, want false", unregistered, err)
+	}
+	checkInit(t, path, initSource)
+}
+
+func TestRegisterFirstModule(t *testing.T) {
+	empty := `package api
+
+import "example.com/app/core/module"
+
+func InitializeModules(deps module.Dependencies) map[string]module.Module {
+	modules := make(map[string]module.Module)
+	return modules
+}
+`
+	path := writeInit(t, empty)
+	registerInit(t, path, "posts")
+	checkInit(t, path, `package api
+
+import (
+	"example.com/app/api/posts"
+	"example.com/app/core/module"
+)
+
+func InitializeModules(deps module.Dependencies) map[string]module.Module {
+	modules := make(map[string]module.Module)
+
+	// Posts module
+	modules["posts"] = posts.Init(deps)
+
+	return modules
+}
+`)
+
+	if _, err := UnregisterModule(path, "example.com/app", "posts"); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "posts") {
+		t.Errorf("init.go still refers to posts:\n%s", content)
+	}
+}
+
+func TestRegisterNameInUse(t *testing.T) {
+	path := writeInit(t, initSource)
+	registerInit(t, path, "module")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`modulemodule "example.com/app/api/module"`,
+		`modules["module"] = modulemodule.Init(deps)`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("init.go has no %s:\n%s", want, content)
+		}
+	}
+}
+
+func TestRegisterSingleImportWithComment(t *testing.T) {
+	path := writeInit(t, `package api
+
+import "example.com/app/core/module" // Module interfaces
+
+func InitializeModules(deps module.Dependencies) map[string]module.Module {
+	modules := make(map[string]module.Module)
+	return modules
+}
+`)
+	registerInit(t, path, "posts")
+	checkInit(t, path, `package api
+
+import (
+	"example.com/app/api/posts"
+	"example.com/app/core/module" // Module interfaces
+)
+
+func InitializeModules(deps module.Dependencies) map[string]module.Module {
+	modules := make(map[string]module.Module)
+
+	// Posts module
+	modules["posts"] = posts.Init(deps)
+
+	return modules
+}
+`)
+}
+
+func TestRegisterUnsupportedInit(t *testing.T) {
+	tests := []struct {
+		name, src, err string
+	}{
+		{"no registration function", "package api\n\nfunc Init() {}\n", "init.go:1: could not find the module registration function"},
+		{"bare return", `package api
+
+import "example.com/app/core/module"
+
+func InitializeModules(deps module.Dependencies) (modules map[string]module.Module) {
+	modules = make(map[string]module.Module)
+	return
+}
+`, "init.go:7: the module registration function must end by returning the map it fills"},
+		{"map literal", `package api
+
+import "example.com/app/core/module"
+
+func InitializeModules(deps module.Dependencies) map[string]module.Module {
+	return map[string]module.Module{}
+}
+`, "init.go:6: the module registration function must end by returning the map it fills"},
+		{"no dependencies", `package api
+
+import "example.com/app/core/module"
+
+func InitializeModules() map[string]module.Module {
+	modules := make(map[string]module.Module)
+	return modules
+}
+`, "init.go:5: InitializeModules has no module.Dependencies parameter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := registeredSource(writeInit(t, tt.src), "example.com/app", "posts")
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Errorf("registeredSource() = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
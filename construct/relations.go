@@ -0,0 +1,221 @@
+package construct
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Fields may refer to other resources of the project:
+//
+//	category:belongs_to          category_id column, picked with a select
+//	author:belongs_to(User)      when the name differs from the model
+//	tags:many_to_many            tag_ids in requests, picked with a multi-select
+//
+// A field such as category_id:uint is a belongs_to relation when a Category
+// resource has been generated, and a plain number otherwise.
+
+// relationTypes lists the field types that refer to another resource
+var relationTypes = []string{"belongs_to", "many_to_many"}
+
+var modelNamePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// generatedResource is a resource of the project other resources can refer to
+type generatedResource struct {
+	Display string // Display field of its records, empty when it has none
+}
+
+// generatedResources are the resources generated in the project, by model
+var generatedResources = map[string]generatedResource{}
+
+// loadProject loads what the generators need to know about a project: its
+// custom inflections and the resources generated so far
+func loadProject(root string) error {
+	if err := loadInflections(root); err != nil {
+		return err
+	}
+	return loadGeneratedResources(root)
+}
+
+// loadGeneratedResources finds the resources of the project from their Go
+// models and, for frontend-only resources, their TypeScript types. Models
+// that do not parse, e.g. with the markers of a merge conflict, are skipped
+// with a warning.
+func loadGeneratedResources(root string) error {
+	generatedResources = map[string]generatedResource{}
+
+	models, _ := filepath.Glob(filepath.Join(root, "api", "models", "*.go"))
+	for _, path := range models {
+		src, err := loadGoSource(path)
+		if err != nil {
+			fmt.Printf("  ⚠️  Skipped %s: %v\n", displayPath(root, path), err)
+			continue
+		}
+		structs := structTypes(src.file)
+		for name, st := range structs {
+			model, ok := strings.CutSuffix(name, "ModelResponse")
+			if !ok || model == "" {
+				continue
+			}
+			// The response embedding a record holds its id and display
+			// field, or a name made from its id when it has none
+			display := ""
+			if fields, ok := structs[model]; ok {
+				columns := jsonNames(fields)
+				for key := range jsonNames(st) {
+					if key != "id" && columns[key] {
+						display = key
+					}
+				}
+			}
+			generatedResources[model] = generatedResource{Display: display}
+		}
+	}
+
+	types, _ := filepath.Glob(filepath.Join(root, "vue", "app", "*", "types", "*.ts"))
+	for _, path := range types {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		displayed := map[string]string{}
+		for _, m := range displayedByPattern.FindAllStringSubmatch(string(content), -1) {
+			displayed[m[2]] = m[1]
+		}
+		for _, m := range interfacePattern.FindAllStringSubmatch(string(content), -1) {
+			model := m[1]
+			if _, ok := generatedResources[model]; ok || !strings.Contains(string(content), "interface "+model+"CreateRequest ") {
+				continue
+			}
+			display, ok := displayed[model]
+			if !ok {
+				// Types generated before the display field was recorded
+				fields := interfaceFields(string(content), model)
+				if fields["name"] != "" {
+					display = "name"
+				} else if fields["title"] != "" {
+					display = "title"
+				}
+			}
+			generatedResources[model] = generatedResource{Display: display}
+		}
+	}
+	return nil
+}
+
+var interfacePattern = regexp.MustCompile(`(?m)^export interface (\w+) \{`)
+
+// jsonNames returns the json names of the fields of a struct
+func jsonNames(st *ast.StructType) map[string]bool {
+	names := map[string]bool{}
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		name, _, _ := strings.Cut(tag.Get("json"), ",")
+		names[name] = true
+	}
+	return names
+}
+
+// parseRelation parses the "(Model)" suffix of a relation type starting at
+// column, returning the related model
+func parseRelation(relationship, list string, column int) (string, error) {
+	if !strings.HasSuffix(list, ")") {
+		return "", &FieldError{
+			Column:     column,
+			Token:      list,
+			Message:    "unterminated model name",
+			Suggestion: "add a closing )",
+		}
+	}
+	model := strings.TrimSpace(list[1 : len(list)-1])
+	if !modelNamePattern.MatchString(model) {
+		return "", &FieldError{
+			Column:     column + 1,
+			Token:      model,
+			Message:    fmt.Sprintf("invalid model name %q", model),
+			Suggestion: fmt.Sprintf("e.g. %s(Category)", relationship),
+		}
+	}
+	return model, nil
+}
+
+// setRelation makes the field a relation to model. A belongs_to field is
+// named after its foreign key, e.g. category_id, and a many_to_many field
+// after the ids sent in requests, e.g. tag_ids; RelatedName is the key of
+// the related records in responses.
+func (f *TemplateField) setRelation(relationship, name, model string) {
+	related := strings.TrimSuffix(strings.TrimSuffix(name, "_id"), "_ids")
+	if relationship == "many_to_many" {
+		if related != name {
+			related = pluralize(related)
+		}
+		if model == "" {
+			model = toPascalCase(singularize(related))
+		}
+		f.Name = singularize(related) + "_ids"
+		f.FieldName = toPascalCase(related)
+		f.TypeScriptType = "number[]"
+		f.ZeroValue = "[]"
+		f.InitialValue = "[]"
+		f.ItemValue = "item." + related + "?.map((record) => record.id) ?? []"
+	} else {
+		if model == "" {
+			model = toPascalCase(related)
+		}
+		f.Name = related + "_id"
+		f.FieldName = toPascalCase(related) + "Id"
+		f.TypeScriptType = "number"
+		f.ZeroValue = "undefined"
+		f.InitialValue = "undefined"
+		f.ItemValue = "item." + f.Name
+		f.Index = true
+	}
+
+	// Records without display field are embedded with a name made from
+	// their id
+	display := "name"
+	if r, ok := generatedResources[model]; ok && r.Display != "" {
+		display = r.Display
+	}
+	f.Type = relationship
+	f.GoType = relationship
+	f.Label = humanize(related)
+	f.Sortable = relationship == "belongs_to"
+	f.Relationship = relationship
+	f.RelatedModel = model
+	f.RelatedName = related
+	f.RelatedRoute = "/" + toKebabCase(pluralize(model))
+	f.RelatedDisplay = display
+	f.RelatedType = "{ id: number; " + display + ": string }"
+}
+
+// relationGoNames returns the Go names of the struct fields a relation adds
+// besides its own, such as the Category object of a CategoryId key
+func (f TemplateField) relationGoNames() []string {
+	switch f.Relationship {
+	case "belongs_to":
+		return []string{toPascalCase(f.RelatedName)}
+	case "many_to_many":
+		return []string{toPascalCase(singularize(f.RelatedName)) + "Ids"}
+	}
+	return nil
+}
+
+// detectRelation reports the model a name_id:uint field refers to, when that
+// resource has been generated
+func detectRelation(name, goType string) (string, bool) {
+	prefix, ok := strings.CutSuffix(name, "_id")
+	if !ok || prefix == "" || (goType != "uint" && goType != "int" && goType != "uint64" && goType != "int64") {
+		return "", false
+	}
+	model := toPascalCase(prefix)
+	_, ok = generatedResources[model]
+	return model, ok
+}
@@ -0,0 +1,56 @@
+package construct
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManyToManyJoinNames(t *testing.T) {
+	data, err := NewTemplateData("Article", []string{"title:string", "categories:many_to_many", "tags:many_to_many(Category)", "topics:many_to_many(Category)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][2]string{
+		"Categories": {"ArticleCategory", "article_categories"},
+		"Tags":       {"ArticleTag", "article_tags"},
+		"Topics":     {"ArticleTopic", "article_topics"},
+	}
+	for _, f := range NewBackendTemplateData("example.com/app", data).Fields {
+		if f.Relationship != "many_to_many" {
+			continue
+		}
+		if got := [2]string{f.JoinModel, f.JoinTable}; got != want[f.Name] {
+			t.Errorf("%s joins through %v, want %v", f.Name, got, want[f.Name])
+		}
+		delete(want, f.Name)
+	}
+	if len(want) > 0 {
+		t.Errorf("no many_to_many fields %v", want)
+	}
+}
+
+func TestLoadGeneratedResourcesSkipsUnparsableModels(t *testing.T) {
+	root := t.TempDir()
+	models := filepath.Join(root, "api", "models")
+	if err := os.MkdirAll(models, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"category.go": "package models\n\ntype Category struct {\n\tId   uint   `json:\"id\"`\n\tName string `json:\"name\"`\n}\n\ntype CategoryModelResponse struct {\n\tId   uint   `json:\"id\"`\n\tName string `json:\"name\"`\n}\n",
+		"post.go":     "package models\n\n<<<<<<< local\ntype Post struct{}\n=======\ntype Post struct{ Id uint }\n>>>>>>> generated\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(models, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { generatedResources = map[string]generatedResource{} })
+
+	if err := loadGeneratedResources(root); err != nil {
+		t.Fatalf("loadGeneratedResources() = %v, want the conflicted model skipped", err)
+	}
+	if got := generatedResources["Category"]; got.Display != "name" {
+		t.Errorf("Category = %+v, want display field name", got)
+	}
+}
@@ -0,0 +1,513 @@
+package construct
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/base-go/mamba"
+)
+
+var renameCmd = &mamba.Command{
+	Use:   "rename [resource] [new-name]",
+	Short: "Rename a generated resource",
+	Long: `Rename a resource across the backend and frontend.
+
+The Go package under api/, the model and request types, routes, events,
+the registration in api/init.go, and the Vue module with its API module,
+store, components and types are renamed. The plan is printed first and
+nothing changes until it is confirmed; if any step fails the project is
+left as it was.
+
+Examples:
+  construct rename Post Article
+  construct rename Post Article --dry-run  # Only print the plan
+  construct rename Post Article --yes      # Rename without asking`,
+	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+		if len(args) != 2 {
+			cmd.Help()
+			os.Exit(1)
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		runRename(args[0], args[1], dryRun, yes)
+	},
+}
+
+func init() {
+	renameCmd.Flags().Bool("dry-run", false, "print the plan without renaming anything")
+	renameCmd.Flags().BoolP("yes", "y", false, "rename without asking for confirmation")
+}
+
+// resourceNamePattern matches a PascalCase Go identifier
+var resourceNamePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+func runRename(oldName, newName string, dryRun, yes bool) {
+	printBanner()
+
+	root, err := findProjectRoot()
+	if err == nil {
+		err = loadProject(root)
+	}
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✏️  Renaming %s to %s...\n", oldName, newName)
+	fmt.Println()
+
+	confirm := func() bool {
+		return yes || confirmRename(oldName, newName)
+	}
+	renamed, err := RenameResource(root, oldName, newName, dryRun, confirm)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	switch {
+	case dryRun:
+		fmt.Println("🔍 Dry run, nothing was renamed")
+	case !renamed:
+		fmt.Println("Cancelled, nothing was renamed. Pass --yes to rename without asking.")
+		os.Exit(1)
+	default:
+		fmt.Printf("🎉 %s renamed to %s\n", oldName, newName)
+	}
+}
+
+// confirmRename asks whether to go ahead with the printed plan. Anything but
+// yes, including no input at all, cancels the rename.
+func confirmRename(oldName, newName string) bool {
+	fmt.Printf("\nRename %s to %s? [y/N] ", oldName, newName)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	fmt.Println()
+	return false
+}
+
+// renamedFile is a file of a resource moved to its new name
+type renamedFile struct {
+	oldPath, newPath string
+	oldRel, newRel   string
+	content          []byte
+}
+
+// RenameResource renames a generated resource. Every file of its backend
+// package, model and Vue module is rewritten under the new name and its
+// registration in api/init.go is updated. Everything is prepared before the
+// first write, and a failed write restores the files already changed. It
+// reports false if nothing was renamed, on a dry run or when confirm
+// declines the printed plan.
+func RenameResource(root, oldName, newName string, dryRun bool, confirm func() bool) (bool, error) {
+	if !resourceNamePattern.MatchString(newName) {
+		return false, fmt.Errorf("invalid resource name %q; use a PascalCase name such as Article", newName)
+	}
+	from, err := NewTemplateData(oldName, nil)
+	if err != nil {
+		return false, err
+	}
+	to, err := NewTemplateData(newName, nil)
+	if err != nil {
+		return false, err
+	}
+	if from.ModuleName == to.ModuleName || toSnakeCase(oldName) == toSnakeCase(newName) {
+		return false, fmt.Errorf("%s and %s map to the same files", oldName, newName)
+	}
+
+	names := newNameReplacer(from, to)
+
+	// The model, the backend package and the Vue module
+	var paths []string
+	if model := filepath.Join(root, "api", "models", toSnakeCase(oldName)+".go"); fileExists(model) {
+		paths = append(paths, model)
+	}
+	for _, dir := range []string{
+		filepath.Join(root, "api", from.ModuleName),
+		filepath.Join(root, "vue", "app", from.DirName),
+	} {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				paths = append(paths, path)
+			}
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	if len(paths) == 0 {
+		return false, fmt.Errorf("%s has not been generated", oldName)
+	}
+
+	var files []renamedFile
+	for _, path := range paths {
+		oldRel := newGeneratedFile(root, path, nil).Rel
+		f := renamedFile{oldPath: path, oldRel: oldRel, newRel: names.replace(oldRel)}
+		f.newPath = filepath.Join(root, filepath.FromSlash(f.newRel))
+		if fileExists(f.newPath) {
+			return false, fmt.Errorf("%s already exists", f.newRel)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		if f.content, err = renameContent(names, f.newRel, content); err != nil {
+			return false, err
+		}
+		files = append(files, f)
+	}
+
+	initPath := filepath.Join(root, "api", "init.go")
+	var initContent []byte
+	if fileExists(initPath) {
+		modulePath, err := projectModulePath(root)
+		if err != nil {
+			return false, err
+		}
+		content, err := renamedSource(initPath, modulePath, from.ModuleName, to.ModuleName)
+		if err != nil {
+			return false, fmt.Errorf("failed to rename module in api/init.go: %w", err)
+		}
+		if current, _ := os.ReadFile(initPath); string(content) != string(current) {
+			initContent = content
+		}
+	}
+
+	for _, f := range files {
+		fmt.Printf("  ~ %-8s  %s → %s\n", "rename", f.oldRel, f.newRel)
+	}
+	if initContent != nil {
+		fmt.Printf("  ~ %-8s  api/init.go\n", actionModify)
+	}
+
+	// Other files are left alone, but may still refer to the old name
+	others, err := referringFiles(root, names, paths)
+	if err != nil {
+		return false, err
+	}
+	if len(others) > 0 {
+		fmt.Println()
+		fmt.Printf("  ⚠️  These files still refer to %s and are not changed:\n", oldName)
+		for _, rel := range others {
+			fmt.Printf("     %s\n", rel)
+		}
+	}
+
+	if dryRun || !confirm() {
+		return false, nil
+	}
+
+	tx := newFileTransaction(root)
+	if err := applyRename(tx, root, names, from, to, files, initPath, initContent); err != nil {
+		tx.rollback()
+		return false, fmt.Errorf("%w; nothing was renamed", err)
+	}
+
+	fmt.Println()
+	for _, f := range files {
+		removeEmptyDirs(filepath.Dir(f.oldPath), root)
+		fmt.Printf("  ✓ Renamed %s → %s\n", f.oldRel, f.newRel)
+	}
+	if initContent != nil {
+		fmt.Println("  ✓ Updated api/init.go")
+	}
+	return true, nil
+}
+
+// applyRename writes the renamed files and moves their manifest entries and
+// recorded schema state, tracking every change in tx
+func applyRename(tx *fileTransaction, root string, names *nameReplacer, from, to *TemplateData, files []renamedFile, initPath string, initContent []byte) error {
+	for _, f := range files {
+		if err := tx.write(f.newPath, f.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.newRel, err)
+		}
+		if err := tx.remove(f.oldPath); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", f.oldRel, err)
+		}
+	}
+	if initContent != nil {
+		if err := tx.write(initPath, initContent); err != nil {
+			return fmt.Errorf("failed to write api/init.go: %w", err)
+		}
+	}
+
+	manifest, err := loadManifest(root)
+	if err != nil {
+		return err
+	}
+	if err := tx.track(filepath.Join(root, manifestPath)); err != nil {
+		return err
+	}
+	for _, f := range files {
+		entry, ok := manifest.Files[f.oldRel]
+		if !ok {
+			continue
+		}
+		oldBase := filepath.Join(root, manifestBaseDir, filepath.FromSlash(f.oldRel))
+		newBase := filepath.Join(root, manifestBaseDir, filepath.FromSlash(f.newRel))
+		for _, path := range []string{oldBase, newBase} {
+			if err := tx.track(path); err != nil {
+				return err
+			}
+		}
+
+		base, known := manifest.base(root, f.oldRel)
+		if err := manifest.forget(root, f.oldRel); err != nil {
+			return err
+		}
+		if !known {
+			continue // Without its base the file is no longer tracked
+		}
+
+		// The renamed base keeps later merges working
+		if base, err = renameContent(names, f.newRel, base); err != nil {
+			return err
+		}
+		renamed := newGeneratedFile(root, f.newPath, base)
+		renamed.Template, renamed.TemplateVersion = entry.Template, entry.TemplateVersion
+		if err := manifest.record(root, renamed); err != nil {
+			return err
+		}
+	}
+	if err := manifest.save(root); err != nil {
+		return fmt.Errorf("failed to write %s: %w", manifestPath, err)
+	}
+
+	state, err := loadSchemaState(root)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, side := range []string{"backend", "frontend"} {
+		if hash, ok := state[from.ResourceName+"/"+side]; ok {
+			delete(state, from.ResourceName+"/"+side)
+			state[to.ResourceName+"/"+side] = hash
+			changed = true
+		}
+	}
+	if changed {
+		if err := tx.track(filepath.Join(root, schemaStatePath)); err != nil {
+			return err
+		}
+		if err := saveSchemaState(root, state); err != nil {
+			return fmt.Errorf("failed to write %s: %w", schemaStatePath, err)
+		}
+	}
+	return nil
+}
+
+// renameContent renames a resource in the content of one of its files
+func renameContent(names *nameReplacer, rel string, content []byte) ([]byte, error) {
+	if filepath.Ext(rel) != ".go" {
+		return []byte(names.replace(string(content))), nil
+	}
+	renamed := []byte(names.replaceExcept(string(content), literalKeyOffsets(content)))
+
+	// Names of a different length change the alignment
+	formatted, err := format.Source(renamed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename %s: %w", rel, err)
+	}
+	return formatted, nil
+}
+
+// literalKeyOffsets returns the offsets of the keys of composite literals in Go
+// source. They name fields of their type, such as the Tag of a
+// validator.ValidationError, rather than the resource.
+func literalKeyOffsets(src []byte) map[int]bool {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		return nil
+	}
+	keys := map[int]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.CompositeLit); ok {
+			for _, elt := range lit.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if ident, ok := kv.Key.(*ast.Ident); ok {
+						keys[fset.Position(ident.Pos()).Offset] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	return keys
+}
+
+// referringFiles lists the other Go and Vue files of the project that use
+// the resource's type or module names
+func referringFiles(root string, names *nameReplacer, skip []string) ([]string, error) {
+	skipped := map[string]bool{}
+	for _, path := range skip {
+		skipped[path] = true
+	}
+
+	var rels []string
+	for _, dir := range []string{filepath.Join(root, "api"), filepath.Join(root, "vue", "app")} {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == "node_modules" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch filepath.Ext(path) {
+			case ".go", ".ts", ".vue":
+			default:
+				return nil
+			}
+			if skipped[path] || path == filepath.Join(root, "api", "init.go") {
+				return nil
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var keys map[int]bool
+			if filepath.Ext(path) == ".go" {
+				keys = literalKeyOffsets(content)
+			}
+			if names.referencedIn(string(content), keys) {
+				rels = append(rels, newGeneratedFile(root, path, nil).Rel)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rels, nil
+}
+
+// nameReplacer rewrites every spelling of a resource name, e.g. BlogPosts,
+// blogPost, blog_posts, blog-post and blog posts, without touching longer
+// words such as Postgres or compost
+type nameReplacer struct {
+	pattern *regexp.Regexp
+	names   map[string]string
+}
+
+func newNameReplacer(from, to *TemplateData) *nameReplacer {
+	pairs := [][2]string{
+		{from.PluralName, to.PluralName},
+		{from.ResourceName, to.ResourceName},
+		{from.LowerPluralName, to.LowerPluralName},
+		{from.LowerResourceName, to.LowerResourceName},
+		{from.ModuleName, to.ModuleName},
+		{toSnakeCase(from.ResourceName), toSnakeCase(to.ResourceName)},
+		{from.DirName, to.DirName},
+		{toKebabCase(from.ResourceName), toKebabCase(to.ResourceName)},
+		{from.HumanPluralName, to.HumanPluralName},
+		{from.HumanName, to.HumanName},
+		{strings.ToLower(from.PluralName), strings.ToLower(to.PluralName)},
+		{strings.ToLower(from.ResourceName), strings.ToLower(to.ResourceName)},
+	}
+
+	r := &nameReplacer{names: map[string]string{}}
+	var forms []string
+	for _, p := range pairs {
+		if _, ok := r.names[p[0]]; !ok {
+			r.names[p[0]] = p[1]
+			forms = append(forms, regexp.QuoteMeta(p[0]))
+		}
+	}
+
+	// Longest first, so Posts wins over Post
+	sort.SliceStable(forms, func(i, j int) bool { return len(forms[i]) > len(forms[j]) })
+	r.pattern = regexp.MustCompile(strings.Join(forms, "|"))
+	return r
+}
+
+// matches returns the [start, end) offsets of the names in s that are whole
+// words. A name ends before anything but a lowercase letter, so Posts and
+// PostService match; a lowercase name also starts after a non-letter, so
+// usePosts matches but compost does not. HTTP methods such as
+// apiClient.post( and swagger's [post] and annotations such as @Tags are not
+// names.
+func (r *nameReplacer) matches(s string) [][]int {
+	var found [][]int
+	for _, m := range r.pattern.FindAllStringIndex(s, -1) {
+		start, end := m[0], m[1]
+		if end < len(s) && unicode.IsLower(rune(s[end])) {
+			continue
+		}
+		if start > 0 && unicode.IsLower(rune(s[start])) && unicode.IsLetter(rune(s[start-1])) {
+			continue
+		}
+		if start > 0 && end < len(s) && (s[start-1] == '.' && (s[end] == '(' || s[end] == '<') || s[start-1] == '[' && s[end] == ']') {
+			continue
+		}
+		if start > 0 && s[start-1] == '@' {
+			continue
+		}
+		found = append(found, m)
+	}
+	return found
+}
+
+// replace renames every name in s
+func (r *nameReplacer) replace(s string) string {
+	return r.replaceExcept(s, nil)
+}
+
+// replaceExcept renames every name in s but those starting at an offset in
+// keep
+func (r *nameReplacer) replaceExcept(s string, keep map[int]bool) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range r.matches(s) {
+		if keep[m[0]] {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(r.names[s[m[0]:m[1]]])
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// referencedIn reports whether s uses one of the PascalCase names as a word
+// of its own, as other modules refer to the model, but at an offset in keep
+func (r *nameReplacer) referencedIn(s string, keep map[int]bool) bool {
+	for _, m := range r.matches(s) {
+		if keep[m[0]] {
+			continue
+		}
+		if unicode.IsUpper(rune(s[m[0]])) && (m[0] == 0 || !isWordByte(s[m[0]-1])) {
+			return true
+		}
+	}
+	return false
+}
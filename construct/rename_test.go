@@ -0,0 +1,45 @@
+package construct
+
+import "testing"
+
+func TestRenameContent(t *testing.T) {
+	from, err := NewTemplateData("Tag", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := NewTemplateData("Label", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := `package tags
+
+// Create creates a tag
+// @Tags App/Tag
+// @Router /tags [post]
+func Create(req *models.CreateTagRequest) error {
+	if req == nil {
+		return validator.ValidationErrors{{Field: "request", Tag: "required"}}
+	}
+	return tagsService.Create(req)
+}
+`
+	want := `package labels
+
+// Create creates a label
+// @Tags App/Label
+// @Router /labels [post]
+func Create(req *models.CreateLabelRequest) error {
+	if req == nil {
+		return validator.ValidationErrors{{Field: "request", Tag: "required"}}
+	}
+	return labelsService.Create(req)
+}
+`
+	got, err := renameContent(newNameReplacer(from, to), "api/labels/controller.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("renameContent() =\n%s\nwant\n%s", got, want)
+	}
+}
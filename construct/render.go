@@ -0,0 +1,177 @@
+package construct
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Every generated file is rendered the same way: the template is parsed
+// together with the partials, the named templates shared between files,
+// executed with templateFuncs, and the output is formatted for its language.
+//
+// Partials live in partials/*.tmpl and are overridden like any other
+// template; a project can also add its own partials to
+// .construct/templates/partials.
+
+// partialsDir holds the shared named templates
+const partialsDir = "partials"
+
+// renderFileTemplate renders the file at t.path from its template
+func renderFileTemplate(root string, t fileTemplate, data interface{}) (*generatedFile, error) {
+	file := newGeneratedFile(root, t.path, nil)
+	file.Template, file.TemplateVersion = t.name, templateVersion(t.template)
+
+	content, err := renderTemplate(root, t, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s:\n%w", file.Rel, err)
+	}
+
+	file.Content, err = formatOutput(t.path, content)
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code for %s from %s: %w", file.Rel, t.source, err)
+	}
+	return file, nil
+}
+
+// renderTemplate executes a template with the shared functions and partials
+func renderTemplate(root string, t fileTemplate, data interface{}) ([]byte, error) {
+	source := t.source
+	if source == "" {
+		source = t.name
+	}
+	sources := map[string]string{source: t.template}
+
+	tmpl := template.New(source).Funcs(templateFuncs)
+	for _, p := range projectPartials(root) {
+		sources[p.source] = p.template
+		if _, err := tmpl.New(p.source).Parse(p.template); err != nil {
+			return nil, templateError(err, sources)
+		}
+	}
+	if _, err := tmpl.Parse(t.template); err != nil {
+		return nil, templateError(err, sources)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, templateError(err, sources)
+	}
+	return buf.Bytes(), nil
+}
+
+// projectPartials returns the partials a project renders with: the built-in
+// ones, or their overrides, followed by any partials the project or user
+// added
+func projectPartials(root string) []fileTemplate {
+	var partials []fileTemplate
+	seen := map[string]bool{}
+	for _, name := range templateNames() {
+		if strings.HasPrefix(name, partialsDir+"/") {
+			partials = append(partials, projectTemplate(root, "", name))
+			seen[name] = true
+		}
+	}
+
+	dirs := templateDirs(root)
+	for i := len(dirs) - 1; i >= 0; i-- {
+		paths, _ := filepath.Glob(filepath.Join(dirs[i], partialsDir, "*.tmpl"))
+		sort.Strings(paths)
+		for _, path := range paths {
+			name := partialsDir + "/" + filepath.Base(path)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			partials = append(partials, projectTemplate(root, "", name))
+		}
+	}
+	return partials
+}
+
+// templateErrorPattern matches the location text/template puts in errors,
+// e.g. "template: frontend/index.vue:12:5: executing ..."
+var templateErrorPattern = regexp.MustCompile(`^template: (.+?):(\d+)(?::(\d+))?: (.*)$`)
+
+// TemplateError is a template that failed to parse or execute, located in
+// the template file
+type TemplateError struct {
+	File    string // Where the template was loaded from
+	Line    int
+	Column  int // 0 when unknown
+	Message string
+	Source  string // The offending line
+}
+
+func (e *TemplateError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:%d", e.File, e.Line)
+	if e.Column > 0 {
+		fmt.Fprintf(&b, ":%d", e.Column)
+	}
+	fmt.Fprintf(&b, ": %s", e.Message)
+
+	if e.Source != "" {
+		prefix := fmt.Sprintf("%6d | ", e.Line)
+		fmt.Fprintf(&b, "\n%s%s", prefix, e.Source)
+		if e.Column > 0 && e.Column <= len(e.Source)+1 {
+			fmt.Fprintf(&b, "\n%s%s^", strings.Repeat(" ", len(prefix)), strings.Repeat(" ", e.Column-1))
+		}
+	}
+	return b.String()
+}
+
+// templateError turns a text/template error into a TemplateError that shows
+// the template line, given the content of each template by source
+func templateError(err error, sources map[string]string) error {
+	m := templateErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+
+	e := &TemplateError{File: m[1], Message: m[4]}
+	e.Line, _ = strconv.Atoi(m[2])
+	e.Column, _ = strconv.Atoi(m[3])
+
+	// Drop the template name text/template repeats in execution errors
+	if i := strings.Index(e.Message, " at <"); i >= 0 && strings.HasPrefix(e.Message, "executing ") {
+		e.Message = e.Message[i+len(" at "):]
+	}
+
+	if content, ok := sources[e.File]; ok {
+		if lines := splitLines(content); e.Line >= 1 && e.Line <= len(lines) {
+			e.Source = strings.ReplaceAll(lines[e.Line-1], "\t", " ")
+		}
+	}
+	return e
+}
+
+// outputFormatters tidy rendered output by file extension
+var outputFormatters = map[string]func([]byte) ([]byte, error){
+	".go":  formatGoSource,
+	".ts":  trimTrailingSpace,
+	".vue": trimTrailingSpace,
+}
+
+// formatOutput formats rendered output for the language of path
+func formatOutput(path string, content []byte) ([]byte, error) {
+	if format, ok := outputFormatters[filepath.Ext(path)]; ok {
+		return format(content)
+	}
+	return content, nil
+}
+
+// trimTrailingSpace removes whitespace left at the end of lines by template
+// actions
+func trimTrailingSpace(content []byte) ([]byte, error) {
+	lines := bytes.Split(content, []byte("\n"))
+	for i, line := range lines {
+		lines[i] = bytes.TrimRight(line, " \t\r")
+	}
+	return bytes.Join(lines, []byte("\n")), nil
+}
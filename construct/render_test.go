@@ -0,0 +1,87 @@
+package construct
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		line     int
+		column   int
+		message  string
+		source   string
+	}{
+		{"unclosed action", "<div>\n{{ .Model }\n</div>\n", 2, 0, "unexpected \"}\" in operand", "{{ .Model }"},
+		{"unknown function", "{{ shout .Model }}\n", 1, 0, `function "shout" not defined`, "{{ shout .Model }}"},
+		{"missing field", "<h1>\n  {{ .Modle }}\n</h1>\n", 2, 5, "<.Modle>: can't evaluate field Modle in type *construct.TemplateData", "  {{ .Modle }}"},
+		{"missing partial", `{{ template "badge" . }}`, 1, 12, `<{{template "badge" .}}>: template "badge" not defined`, `{{ template "badge" . }}`},
+	}
+	data, err := NewTemplateData("Post", []string{"title:string"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := fileTemplate{path: "index.vue", name: "frontend/index.vue", template: tt.template, source: ".construct/templates/frontend/index.vue"}
+			_, err := renderTemplate(t.TempDir(), tmpl, data)
+			e, ok := err.(*TemplateError)
+			if !ok {
+				t.Fatalf("renderTemplate() = %v, want a TemplateError", err)
+			}
+			if e.File != tmpl.source || e.Line != tt.line || e.Column != tt.column {
+				t.Errorf("error at %s:%d:%d, want %s:%d:%d", e.File, e.Line, e.Column, tmpl.source, tt.line, tt.column)
+			}
+			if e.Message != tt.message || e.Source != tt.source {
+				t.Errorf("error %q on %q, want %q on %q", e.Message, e.Source, tt.message, tt.source)
+			}
+		})
+	}
+}
+
+func TestFormatOutput(t *testing.T) {
+	tests := []struct {
+		path, content, want string
+	}{
+		{"index.vue", "<div>  \n\t<p>x</p>\t\n</div>\n", "<div>\n\t<p>x</p>\n</div>\n"},
+		{"store.ts", "const a = 1 \r\n", "const a = 1\n"},
+		{"service.go", "package posts\nimport \"strings\"\nfunc  F( ) {}\n", "package posts\n\nfunc F() {}\n"},
+		{"README.md", "text  \n", "text  \n"},
+	}
+	for _, tt := range tests {
+		got, err := formatOutput(tt.path, []byte(tt.content))
+		if err != nil || string(got) != tt.want {
+			t.Errorf("formatOutput(%s, %q) = %q, %v, want %q", tt.path, tt.content, got, err, tt.want)
+		}
+	}
+}
+
+func TestProjectPartials(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // No user template overrides
+	root := t.TempDir()
+	dir := filepath.Join(root, templateOverrideDir, partialsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"table_cell.tmpl": `{{ define "table_cell" }}cell {{ .Name }}{{ end }}`,
+		"badge.tmpl":      `{{ define "badge" }}<Badge>{{ . }}</Badge>{{ end }}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := NewTemplateData("Post", []string{"title:string"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := fileTemplate{path: "index.vue", name: "frontend/index.vue", template: `{{ range .Fields }}{{ template "table_cell" . }} {{ template "badge" .Name }}{{ end }}`}
+	got, err := renderTemplate(root, tmpl, data)
+	if want := "cell title <Badge>title</Badge>"; err != nil || string(got) != want {
+		t.Errorf("renderTemplate() = %q, %v, want %q", got, err, want)
+	}
+}
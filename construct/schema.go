@@ -0,0 +1,419 @@
+package construct
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema describes many resources in one file, e.g. construct.schema.yaml:
+//
+//	resources:
+//	  - name: Category
+//	    fields:
+//	      - name:string:required:unique
+//	  - name: Post
+//	    fields:
+//	      - title:string:required:max=255
+//	      - body:text?
+//	    belongs_to: [Category]
+//	  - name: Book
+//	    fields:
+//	      - isbn:string:unique
+//	      - title:string
+//	    display: isbn
+type Schema struct {
+	Resources []SchemaResource `yaml:"resources"`
+}
+
+// SchemaResource describes a single resource in a schema file
+type SchemaResource struct {
+	Name       string   `yaml:"name"`
+	Fields     []string `yaml:"fields"`
+	BelongsTo  []string `yaml:"belongs_to"`
+	ManyToMany []string `yaml:"many_to_many"`
+	Display    string   `yaml:"display"`
+	Backend    *bool    `yaml:"backend"`
+	Frontend   *bool    `yaml:"frontend"`
+}
+
+// schemaStatePath records the last applied definition of each resource
+const schemaStatePath = ".construct/schema.json"
+
+// Schema resource statuses
+const (
+	schemaAdded     = "added"
+	schemaChanged   = "changed"
+	schemaUnchanged = "unchanged"
+)
+
+// LoadSchema reads and validates a schema file
+func LoadSchema(path string) (*Schema, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	var schema Schema
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	if err := schema.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	return &schema, nil
+}
+
+// validate checks resource names, fields and relationship targets
+func (s *Schema) validate() error {
+	if len(s.Resources) == 0 {
+		return fmt.Errorf("no resources defined")
+	}
+
+	names := make([]string, 0, len(s.Resources))
+	seen := map[string]bool{}
+	for i, r := range s.Resources {
+		if r.Name == "" {
+			return fmt.Errorf("resource #%d has no name", i+1)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("resource %q is defined more than once", r.Name)
+		}
+		seen[r.Name] = true
+		names = append(names, r.Name)
+	}
+
+	for _, r := range s.Resources {
+		data, err := r.templateData()
+		if err == nil {
+			err = data.checkSlugSources(nil)
+		}
+		if err != nil {
+			return fmt.Errorf("resource %s: %w", r.Name, err)
+		}
+		for _, target := range r.dependencies() {
+			if !seen[target] {
+				return fmt.Errorf("resource %s: unknown related resource %q (%s)", r.Name, target, suggestion(target, names))
+			}
+		}
+	}
+
+	return nil
+}
+
+// fieldArgs returns the field definitions including the relations to the
+// resources listed under belongs_to and many_to_many
+func (r SchemaResource) fieldArgs() []string {
+	args := append([]string{}, r.Fields...)
+	for _, target := range r.BelongsTo {
+		name := toSnakeCase(target)
+		if !r.declares(name, name+"_id") {
+			args = append(args, name+":belongs_to("+target+")")
+		}
+	}
+	for _, target := range r.ManyToMany {
+		name := toSnakeCase(pluralize(target))
+		if !r.declares(name, toSnakeCase(target)+"_ids") {
+			args = append(args, name+":many_to_many("+target+")")
+		}
+	}
+	return args
+}
+
+// templateData creates the template data of the resource
+func (r SchemaResource) templateData() (*TemplateData, error) {
+	return displayedTemplateData(r.Name, r.fieldArgs(), r.Display)
+}
+
+// declares reports whether the fields of the resource define any of names
+func (r SchemaResource) declares(names ...string) bool {
+	for _, f := range r.Fields {
+		for _, name := range names {
+			if strings.HasPrefix(f, name+":") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dependencies returns the resources this resource refers to
+func (r SchemaResource) dependencies() []string {
+	return append(append([]string{}, r.BelongsTo...), r.ManyToMany...)
+}
+
+// hash fingerprints the resource definition together with the versions of
+// the templates generating it, to detect changes between runs
+func (r SchemaResource) hash(templates []fileTemplate) string {
+	content, _ := json.Marshal(r)
+	h := sha256.New()
+	h.Write(content)
+	for _, t := range templates {
+		fmt.Fprintf(h, "\n%s %s", t.name, templateVersion(t.template))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sideTemplates returns the templates generating a side of a resource,
+// including the partials they may use
+func sideTemplates(root string, data *TemplateData, side string) ([]fileTemplate, error) {
+	var templates []fileTemplate
+	if side == "frontend" {
+		templates = frontendTemplates(root, data)
+	} else {
+		modulePath, err := projectModulePath(root)
+		if err != nil {
+			return nil, err
+		}
+		templates = backendTemplates(root, NewBackendTemplateData(modulePath, data))
+	}
+	return append(templates, projectPartials(root)...), nil
+}
+
+// deletedFiles reports whether a file the manifest records as generated
+// from the templates has since been deleted
+func deletedFiles(root string, manifest *Manifest, templates []fileTemplate) bool {
+	for _, t := range templates {
+		if t.path == "" {
+			continue // Partials generate no file
+		}
+		rel := newGeneratedFile(root, t.path, nil).Rel
+		if _, ok := manifest.Files[rel]; ok && !fileExists(t.path) {
+			return true
+		}
+	}
+	return false
+}
+
+// generates reports whether the resource generates the given side
+func (r SchemaResource) generates(side *bool) bool {
+	return side == nil || *side
+}
+
+// Ordered returns the resources sorted so that related resources are
+// generated before the resources that refer to them. Resources without
+// dependencies between them keep their order from the file.
+func (s *Schema) Ordered() ([]SchemaResource, error) {
+	byName := map[string]SchemaResource{}
+	for _, r := range s.Resources {
+		byName[r.Name] = r
+	}
+
+	var ordered []SchemaResource
+	state := map[string]int{} // 0 = unvisited, 1 = visiting, 2 = done
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case 1:
+			start := 0
+			for i, p := range path {
+				if p == name {
+					start = i
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return fmt.Errorf("circular relationship: %s", strings.Join(cycle, " → "))
+		case 2:
+			return nil
+		}
+
+		state[name] = 1
+		path = append(path, name)
+		for _, dep := range byName[name].dependencies() {
+			if dep == name {
+				continue // Self references need no ordering
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = 2
+		ordered = append(ordered, byName[name])
+		return nil
+	}
+
+	for _, r := range s.Resources {
+		if err := visit(r.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// loadSchemaState reads the hashes recorded by the previous run
+func loadSchemaState(root string) (map[string]string, error) {
+	state := map[string]string{}
+
+	content, err := os.ReadFile(filepath.Join(root, schemaStatePath))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", schemaStatePath, err)
+	}
+	return state, nil
+}
+
+// saveSchemaState records the hashes of the applied resources
+func saveSchemaState(root string, state map[string]string) error {
+	path := filepath.Join(root, schemaStatePath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// GenerateFromSchema generates every resource in the schema in dependency
+// order, skipping resources whose definition has not changed since the last
+// run. It returns the status of each resource by name.
+func GenerateFromSchema(root string, schema *Schema, backend, frontend bool, opts GenerateOptions) (map[string]string, error) {
+	resources, err := schema.Ordered()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadSchemaState(root)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := loadManifest(root)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := map[string]string{}
+	for _, r := range resources {
+		// Pick up the resources generated so far, which relations refer to
+		if err := loadGeneratedResources(root); err != nil {
+			return statuses, err
+		}
+		data, err := r.templateData()
+		if err != nil {
+			return statuses, fmt.Errorf("%s: %w", r.Name, err)
+		}
+
+		// Backend and frontend are tracked separately so that running g:f
+		// does not mark the backend of a resource as generated
+		var sides []string
+		if backend && r.generates(r.Backend) {
+			sides = append(sides, "backend")
+		}
+		if frontend && r.generates(r.Frontend) {
+			sides = append(sides, "frontend")
+		}
+
+		// A side also changes with its templates, and when files generated
+		// for it were deleted
+		status := schemaUnchanged
+		hashes := map[string]string{}
+		for _, side := range sides {
+			templates, err := sideTemplates(root, data, side)
+			if err != nil {
+				return statuses, fmt.Errorf("%s: %w", r.Name, err)
+			}
+			hashes[side] = r.hash(templates)
+
+			previous, applied := state[r.Name+"/"+side]
+			switch {
+			case !applied:
+				status = schemaAdded
+			case status == schemaUnchanged && (previous != hashes[side] || deletedFiles(root, manifest, templates)):
+				status = schemaChanged
+			}
+		}
+		statuses[r.Name] = status
+		if status == schemaUnchanged {
+			continue
+		}
+
+		fmt.Printf("📦 %s (%s)\n", r.Name, status)
+		for _, side := range sides {
+			generate, label := GenerateBackend, "Go"
+			if side == "frontend" {
+				generate, label = GenerateFrontend, "Vue"
+			}
+			if err := generate(root, data, opts); err != nil {
+				delete(statuses, r.Name)
+				return statuses, fmt.Errorf("%s: %s generation failed: %w", r.Name, label, err)
+			}
+			if opts.preview() {
+				continue
+			}
+
+			// Record progress after each step so a failure part-way through
+			// does not regenerate what already succeeded
+			state[r.Name+"/"+side] = hashes[side]
+			if err := saveSchemaState(root, state); err != nil {
+				return statuses, fmt.Errorf("failed to write %s: %w", schemaStatePath, err)
+			}
+		}
+		fmt.Println()
+	}
+
+	return statuses, nil
+}
+
+// printSchemaReport summarises what happened to each resource
+func printSchemaReport(schema *Schema, statuses map[string]string) {
+	width := 0
+	for _, r := range schema.Resources {
+		width = max(width, len(r.Name))
+	}
+
+	names := make([]string, 0, len(statuses))
+	for _, r := range schema.Resources {
+		if _, ok := statuses[r.Name]; ok {
+			names = append(names, r.Name)
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return statusRank(statuses[names[i]]) < statusRank(statuses[names[j]])
+	})
+
+	for _, name := range names {
+		symbol := "="
+		switch statuses[name] {
+		case schemaAdded:
+			symbol = "+"
+		case schemaChanged:
+			symbol = "~"
+		}
+		fmt.Printf("   %s %-*s  %s\n", symbol, width, name, statuses[name])
+	}
+}
+
+func statusRank(status string) int {
+	switch status {
+	case schemaAdded:
+		return 0
+	case schemaChanged:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,55 @@
+package construct
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateFromSchemaDetectsChanges(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // No user template overrides
+	root := t.TempDir()
+	schema := &Schema{Resources: []SchemaResource{{Name: "Post", Fields: []string{"title:string"}}}}
+	generate := func() string {
+		t.Helper()
+		statuses, err := GenerateFromSchema(root, schema, false, true, GenerateOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return statuses["Post"]
+	}
+
+	if got := generate(); got != schemaAdded {
+		t.Fatalf("first run reports Post %s, want %s", got, schemaAdded)
+	}
+	if got := generate(); got != schemaUnchanged {
+		t.Fatalf("second run reports Post %s, want %s", got, schemaUnchanged)
+	}
+
+	page := filepath.Join(root, "vue", "app", "posts", "pages", "index.vue")
+	if err := os.Remove(page); err != nil {
+		t.Fatal(err)
+	}
+	if got := generate(); got != schemaChanged || !fileExists(page) {
+		t.Fatalf("run after deleting index.vue reports Post %s, want %s with the page generated again", got, schemaChanged)
+	}
+
+	override := filepath.Join(root, templateOverrideDir, "frontend", "index.vue")
+	if err := os.MkdirAll(filepath.Dir(override), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(override, []byte("<template><h1>Posts</h1></template>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := generate(); got != schemaChanged {
+		t.Fatalf("run after overriding a template reports Post %s, want %s", got, schemaChanged)
+	}
+	if got := generate(); got != schemaUnchanged {
+		t.Fatalf("last run reports Post %s, want %s", got, schemaUnchanged)
+	}
+
+	schema.Resources[0].Fields = append(schema.Resources[0].Fields, "body:text")
+	if got := generate(); got != schemaChanged {
+		t.Fatalf("run after adding a field reports Post %s, want %s", got, schemaChanged)
+	}
+}
@@ -0,0 +1,177 @@
+package construct
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Slug fields hold a unique URL slug derived from another field:
+//
+//	slug:slug(title)      "Hello, World!" gives hello-world
+//	slug:slug             derived from the display field
+//
+// The service derives the slug on create, and again on update when the
+// source changes, numbering it (hello-world-2) when another record has it.
+// Slugs are left out of requests, and the form previews them read-only.
+// The controller finds a record by its slug at GET /<plural>/by-slug/:slug.
+
+// slugSize is the size of a slug column, which holds 100 characters and
+// the number telling it from other records' slugs
+const slugSize = "255"
+
+// parseSlugSource parses the "(title)" of a slug type starting at column
+func parseSlugSource(list string, column int) (string, error) {
+	if !strings.HasSuffix(list, ")") {
+		return "", &FieldError{
+			Column:     column,
+			Token:      list,
+			Message:    "unterminated slug source",
+			Suggestion: "add a closing )",
+		}
+	}
+	source := strings.TrimSpace(list[1 : len(list)-1])
+	if !fieldNamePattern.MatchString(source) {
+		return "", &FieldError{
+			Column:     column + 1,
+			Token:      source,
+			Message:    fmt.Sprintf("invalid slug source %q", source),
+			Suggestion: "name the field to derive it from, e.g. slug(title)",
+		}
+	}
+	return source, nil
+}
+
+// setSlug makes the field a unique slug derived from source, which is the
+// display field when empty
+func (f *TemplateField) setSlug(source string) {
+	f.IsSlug = true
+	f.SlugSource = source
+	f.Unique = true
+}
+
+// resolveSlugSources derives the slugs without a source from the display
+// field, and checks that the sources among the fields are plain text
+func resolveSlugSources(fields []TemplateField, fieldArgs []string, displayField string) error {
+	for i := range fields {
+		f := &fields[i]
+		if !f.IsSlug {
+			continue
+		}
+		if f.SlugSource == "" {
+			if displayField == "" || displayField == f.Name {
+				return &FieldError{
+					Arg:        fieldArgs[i],
+					Index:      i + 1,
+					Column:     strings.IndexByte(fieldArgs[i], ':') + 2,
+					Token:      "slug",
+					Message:    "no display field to derive the slug from",
+					Suggestion: "name its source, e.g. slug(title)",
+				}
+			}
+			f.SlugSource = displayField
+		}
+		for _, source := range fields {
+			if source.Name != f.SlugSource || isSlugSourceType(source.Type) && !source.IsNullable {
+				continue
+			}
+			fe := &FieldError{
+				Arg:        fieldArgs[i],
+				Index:      i + 1,
+				Column:     strings.IndexByte(fieldArgs[i], ':') + 2,
+				Token:      "slug",
+				Message:    fmt.Sprintf("cannot derive a slug from %s, only from string and text fields that are not nullable", source.Name),
+				Suggestion: "e.g. slug(title)",
+			}
+			if j := strings.Index(fieldArgs[i], "("+f.SlugSource+")"); j >= 0 {
+				fe.Column, fe.Token = j+2, f.SlugSource
+			}
+			return fe
+		}
+	}
+	return nil
+}
+
+// isSlugSourceType reports whether a slug can be derived from a field type
+func isSlugSourceType(fieldType string) bool {
+	return fieldType == "string" || fieldType == "text"
+}
+
+// checkSlugSources checks that every slug has its source among the fields
+// or the existing fields of the resource, as read by generatedFields
+func (d *TemplateData) checkSlugSources(existing map[string]string) error {
+	for _, f := range d.Fields {
+		if !f.IsSlug || d.hasField(f.SlugSource) {
+			continue
+		}
+		fieldType, ok := existing[f.SlugSource]
+		if !ok {
+			return fmt.Errorf("%s has no %s field to derive %s from, name its source with %s:slug(<field>)", d.ResourceName, f.SlugSource, f.Name, f.Name)
+		}
+		if fieldType, _, _ = strings.Cut(fieldType, ":"); !isSlugSourceType(fieldType) {
+			return fmt.Errorf("cannot derive the %s slug from %s, only from string and text fields that are not nullable", f.Name, f.SlugSource)
+		}
+	}
+	return nil
+}
+
+// hasField reports whether the resource has a field of the given name
+func (d *TemplateData) hasField(name string) bool {
+	for _, f := range d.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// slugSourceOf reads the source of a slug from the comment of its model
+// field or TypeScript property, e.g. "Derived from title"
+func slugSourceOf(comment string) (string, bool) {
+	source, ok := strings.CutPrefix(strings.TrimSpace(comment), "Derived from ")
+	return source, ok && fieldNamePattern.MatchString(source)
+}
+
+// derivedSlug returns the slug among the existing fields, as read by
+// generatedFields, that is derived from the named field
+func derivedSlug(existing map[string]string, name string) (string, bool) {
+	for slug, fieldType := range existing {
+		if fieldType == "slug("+name+")" {
+			return slug, true
+		}
+	}
+	return "", false
+}
+
+// slugGoNames returns the methods deriving a slug and finding a record by
+// it, which the statements of the field call
+func (f TemplateField) slugGoNames() []string {
+	if !f.IsSlug {
+		return nil
+	}
+	return []string{"set" + f.FieldName, "GetBy" + f.FieldName}
+}
+
+// slugFile renders models/slug.go, which the slug fields of every resource
+// share, or returns nil when the fields have none
+func slugFile(root string, data *BackendTemplateData) (*generatedFile, error) {
+	for _, f := range data.Fields {
+		if f.IsSlug {
+			t := projectTemplate(root, filepath.Join(root, "api", "models", "slug.go"), "base/slug.tmpl")
+			return renderFileTemplate(root, t, data)
+		}
+	}
+	return nil, nil
+}
+
+// slugsFile renders utils/slugs.ts, which previews the slug fields of every
+// resource, or returns nil when the fields have none
+func slugsFile(root string, data *TemplateData) (*generatedFile, error) {
+	for _, f := range data.Fields {
+		if f.IsSlug {
+			t := projectTemplate(root, filepath.Join(root, "vue", "app", "utils", "slugs.ts"), "frontend/slugs.ts")
+			return renderFileTemplate(root, t, data)
+		}
+	}
+	return nil, nil
+}
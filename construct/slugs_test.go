@@ -0,0 +1,145 @@
+package construct
+
+import "testing"
+
+func TestSlugFields(t *testing.T) {
+	tests := []struct {
+		fields  []string
+		source  string
+		message string // FieldError on the slug, "" when valid
+	}{
+		{fields: []string{"title:string", "slug:slug(title)"}, source: "title"},
+		{fields: []string{"title:string", "slug:slug( title )"}, source: "title"},
+		{fields: []string{"name:string", "body:text", "slug:slug"}, source: "name"},
+		{fields: []string{"body:text", "slug:slug(body)"}, source: "body"},
+		{fields: []string{"slug:slug(title)"}, source: "title"}, // An existing field, see checkSlugSources
+		{fields: []string{"views:int", "slug:slug"}, message: "no display field to derive the slug from"},
+		{fields: []string{"title:string?", "slug:slug(title)"}, message: "cannot derive a slug from title, only from string and text fields that are not nullable"},
+		{fields: []string{"views:int", "slug:slug(views)"}, message: "cannot derive a slug from views, only from string and text fields that are not nullable"},
+		{fields: []string{"title:string", "slug:slug(title):required"}, message: `modifier "required" does not apply to slug fields`},
+		{fields: []string{"title:string", "slug:slug(9)"}, message: `invalid slug source "9"`},
+		{fields: []string{"title:string", "slug:slug(title"}, message: "unterminated slug source"},
+	}
+	for _, tt := range tests {
+		data, err := NewTemplateData("Post", tt.fields)
+		if tt.message != "" {
+			if fe, ok := err.(*FieldError); !ok || fe.Message != tt.message {
+				t.Errorf("NewTemplateData(%q) = %v, want %q", tt.fields, err, tt.message)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewTemplateData(%q) = %v", tt.fields, err)
+			continue
+		}
+		f := data.Fields[len(data.Fields)-1]
+		if !f.IsSlug || f.SlugSource != tt.source || !f.Unique || f.GORMTag != "size:255;uniqueIndex" || f.BindingTag != "" {
+			t.Errorf("%q: slug %v from %q, unique %v, gorm %q, binding %q, want a unique slug from %q", tt.fields, f.IsSlug, f.SlugSource, f.Unique, f.GORMTag, f.BindingTag, tt.source)
+		}
+	}
+}
+
+func TestCheckSlugSources(t *testing.T) {
+	tests := []struct {
+		existing map[string]string
+		valid    bool
+	}{
+		{map[string]string{"title": "string"}, true},
+		{map[string]string{"title": "text:required"}, true},
+		{map[string]string{"title": "int"}, false},
+		{map[string]string{"name": "string"}, false},
+		{nil, false},
+	}
+	data, err := NewTemplateData("Post", []string{"slug:slug(title)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		if err := data.checkSlugSources(tt.existing); (err == nil) != tt.valid {
+			t.Errorf("checkSlugSources(%v) = %v, want valid %v", tt.existing, err, tt.valid)
+		}
+	}
+}
+
+func TestSlugSourceOf(t *testing.T) {
+	tests := []struct {
+		comment, source string
+		ok              bool
+	}{
+		{"Derived from title", "title", true},
+		{"  Derived from blog_title ", "blog_title", true},
+		{"Derived from the title", "", false},
+		{"Title of the post", "", false},
+	}
+	for _, tt := range tests {
+		if source, ok := slugSourceOf(tt.comment); ok != tt.ok || ok && source != tt.source {
+			t.Errorf("slugSourceOf(%q) = %q, %v, want %q, %v", tt.comment, source, ok, tt.source, tt.ok)
+		}
+	}
+
+	existing := map[string]string{"title": "string", "slug": "slug(title)"}
+	if slug, ok := derivedSlug(existing, "title"); !ok || slug != "slug" {
+		t.Errorf("derivedSlug(title) = %q, %v, want slug", slug, ok)
+	}
+	if slug, ok := derivedSlug(existing, "slug"); ok {
+		t.Errorf("derivedSlug(slug) = %q, want none", slug)
+	}
+}
+
+// slugTest checks the generated Slugify and SlugHasBase
+const slugTest = `package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		text, slug string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  Go 1.25 -- released  ", "go-1-25-released"},
+		{"Crème Brûlée", "crème-brûlée"},
+		{"ÜBER straße", "über-straße"},
+		{"!!!", ""},
+		{"", ""},
+		{strings.Repeat("a", 99) + " b", strings.Repeat("a", 99)},
+		{strings.Repeat("é", 120), strings.Repeat("é", 100)},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.text); got != tt.slug {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.text, got, tt.slug)
+		}
+	}
+}
+
+func TestSlugHasBase(t *testing.T) {
+	tests := []struct {
+		slug, base string
+		has        bool
+	}{
+		{"hello-world", "hello-world", true},
+		{"hello-world-2", "hello-world", true},
+		{"hello-world-12", "hello-world", true},
+		{"hello-world-again", "hello-world", false},
+		{"hello-world-", "hello-world", false},
+		{"hello-world-2", "hello", false},
+		{"hello", "hello-world", false},
+	}
+	for _, tt := range tests {
+		if got := SlugHasBase(tt.slug, tt.base); got != tt.has {
+			t.Errorf("SlugHasBase(%q, %q) = %v, want %v", tt.slug, tt.base, got, tt.has)
+		}
+	}
+}
+`
+
+func TestGeneratedSlug(t *testing.T) {
+	data, err := NewTemplateData("Post", []string{"title:string", "slug:slug(title)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := builtinFile(t, "slug.go", "base/slug.tmpl", NewBackendTemplateData("example.com/app", data))
+	testGeneratedModels(t, file, &generatedFile{Path: "slug_test.go", Content: []byte(slugTest)})
+}
@@ -2,14 +2,63 @@ package construct
 
 import (
 	_ "embed"
+	"reflect"
+	"strings"
+	"text/template"
 )
 
+// templateFuncs are the helpers available to every template, built-in or
+// overridden:
+//
+//	ToSnakeCase "BlogPost"          blog_post
+//	ToKebabCase "BlogPost"          blog-post
+//	ToPascalCase "featured_image"   FeaturedImage
+//	ToCamelCase "BlogPost"          blogPost
+//	ToHuman "featured_image"        Featured image
+//	ToPlural "Category"             Categories
+//	ToSingular "Categories"         Category
+//	TrimIdSuffix "CategoryId"       Category
+//	toLower, toUpper                change case
+//	hasPrefix, hasSuffix, contains  the strings functions of the same name
+//	hasField .Fields "text"         whether any field has the given Type
+var templateFuncs = template.FuncMap{
+	"ToSnakeCase":  toSnakeCase,
+	"ToKebabCase":  toKebabCase,
+	"ToPascalCase": toPascalCase,
+	"ToCamelCase":  toCamelCase,
+	"ToHuman":      humanize,
+	"ToPlural":     pluralize,
+	"ToSingular":   singularize,
+	"TrimIdSuffix": func(s string) string { return strings.TrimSuffix(strings.TrimSuffix(s, "ID"), "Id") },
+	"toLower":      strings.ToLower,
+	"toUpper":      strings.ToUpper,
+	"hasSuffix":    strings.HasSuffix,
+	"hasPrefix":    strings.HasPrefix,
+	"contains":     strings.Contains,
+	"hasField":     hasField,
+}
+
+// hasField reports whether any element of fields has the given Type
+func hasField(fields interface{}, fieldType string) bool {
+	v := reflect.ValueOf(fields)
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < v.Len(); i++ {
+		f := reflect.Indirect(v.Index(i)).FieldByName("Type")
+		if f.IsValid() && f.Kind() == reflect.String && f.String() == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
 // Vue frontend templates
 //go:embed templates/frontend/index.vue
 var vueIndexTemplate string
 
-//go:embed templates/frontend/composable.ts
-var vueComposableTemplate string
+//go:embed templates/frontend/api.ts
+var vueAPITemplate string
 
 //go:embed templates/frontend/types.ts
 var vueTypesTemplate string
@@ -21,4 +70,78 @@ var vueStoreTemplate string
 var vueAddModalTemplate string
 
 //go:embed templates/frontend/DeleteModal.vue
-var vueDeleteModalTemplate string
\ No newline at end of file
+var vueDeleteModalTemplate string
+
+//go:embed templates/frontend/dates.ts
+var vueDatesTemplate string
+
+//go:embed templates/frontend/json.ts
+var vueJSONTemplate string
+
+//go:embed templates/frontend/decimals.ts
+var vueDecimalsTemplate string
+
+//go:embed templates/frontend/slugs.ts
+var vueSlugsTemplate string
+
+//go:embed templates/frontend/requests.ts
+var vueRequestsTemplate string
+
+// Go backend templates
+//go:embed templates/base/model.tmpl
+var goModelTemplate string
+
+//go:embed templates/base/service.tmpl
+var goServiceTemplate string
+
+//go:embed templates/base/controller.tmpl
+var goControllerTemplate string
+
+//go:embed templates/base/validator.tmpl
+var goValidatorTemplate string
+
+//go:embed templates/base/module.tmpl
+var goModuleTemplate string
+
+//go:embed templates/base/nullable.tmpl
+var goNullableTemplate string
+
+//go:embed templates/base/decimal.tmpl
+var goDecimalTemplate string
+
+//go:embed templates/base/slug.tmpl
+var goSlugTemplate string
+
+// Partials shared between templates
+//go:embed templates/partials/form_field.tmpl
+var formFieldPartial string
+
+//go:embed templates/partials/table_cell.tmpl
+var tableCellPartial string
+
+// builtinTemplates maps each template name to the template built into the CLI
+func builtinTemplates() map[string]string {
+	return map[string]string{
+		"frontend/index.vue":       vueIndexTemplate,
+		"frontend/api.ts":          vueAPITemplate,
+		"frontend/types.ts":        vueTypesTemplate,
+		"frontend/store.ts":        vueStoreTemplate,
+		"frontend/AddModal.vue":    vueAddModalTemplate,
+		"frontend/DeleteModal.vue": vueDeleteModalTemplate,
+		"frontend/dates.ts":        vueDatesTemplate,
+		"frontend/json.ts":         vueJSONTemplate,
+		"frontend/decimals.ts":     vueDecimalsTemplate,
+		"frontend/slugs.ts":        vueSlugsTemplate,
+		"frontend/requests.ts":     vueRequestsTemplate,
+		"base/model.tmpl":          goModelTemplate,
+		"base/service.tmpl":        goServiceTemplate,
+		"base/controller.tmpl":     goControllerTemplate,
+		"base/validator.tmpl":      goValidatorTemplate,
+		"base/module.tmpl":         goModuleTemplate,
+		"base/nullable.tmpl":       goNullableTemplate,
+		"base/decimal.tmpl":        goDecimalTemplate,
+		"base/slug.tmpl":           goSlugTemplate,
+		"partials/form_field.tmpl": formFieldPartial,
+		"partials/table_cell.tmpl": tableCellPartial,
+	}
+}
@@ -0,0 +1,50 @@
+import { apiClient } from '~/core/api/client'
+import { request, toQuery, type Paginated, type SelectOption } from '~/utils/requests'
+import type { {{.ResourceName}}, {{.ResourceName}}CreateRequest, {{.ResourceName}}UpdateRequest, {{.ResourceName}}QueryParams } from '../types/{{.LowerResourceName}}'
+
+// Requests of the {{.HumanName}} endpoints, each returning the body of the
+// response or throwing an ApiError
+export const {{.LowerPluralName}}Api = {
+  // Page of {{.HumanPluralName}} matching the search, filters and sorting
+  list: (params: {{.ResourceName}}QueryParams = {}) =>
+    request<Paginated<{{.ResourceName}}>>(() => apiClient.get('{{.RoutePath}}', { params: toQuery(params) }), 'Failed to fetch {{.HumanPluralName}}'),
+
+  // Every {{.HumanName}} by its display name, for selects
+  listAll: () =>
+    request<SelectOption[] | null>(() => apiClient.get('{{.RoutePath}}/all'), 'Failed to fetch {{.HumanPluralName}}').then((options) => options ?? []),
+
+  get: (id: number) =>
+    request<{{.ResourceName}}>(() => apiClient.get('{{.RoutePath}}/' + id), 'Failed to fetch {{.HumanName}}'),
+
+  // construct:fields lookups
+  {{range .Fields}}{{if .IsSlug}}getBy{{.FieldName}}: ({{ToCamelCase .Name}}: string) =>
+    request<{{$.ResourceName}}>(() => apiClient.get('{{$.RoutePath}}/by-{{ToKebabCase .Name}}/' + encodeURIComponent({{ToCamelCase .Name}})), 'Failed to fetch {{$.HumanName}}'),
+  {{end}}{{end}}// construct:end
+
+  create: (data: {{.ResourceName}}CreateRequest) =>
+    request<{{.ResourceName}}>(() => apiClient.post('{{.RoutePath}}', data), 'Failed to create {{.HumanName}}'),
+
+  update: (id: number, data: {{.ResourceName}}UpdateRequest) =>
+    request<{{.ResourceName}}>(() => apiClient.put('{{.RoutePath}}/' + id, data), 'Failed to update {{.HumanName}}'),
+
+  delete: (id: number) =>
+    request<unknown>(() => apiClient.delete('{{.RoutePath}}/' + id), 'Failed to delete {{.HumanName}}').then(() => undefined),
+
+  // Uploads a file to an attachment field, e.g. avatar, reporting progress in
+  // percent
+  uploadAttachment: (id: number, field: string, file: File, onProgress?: (percent: number) => void) => {
+    const form = new FormData()
+    form.append('file', file)
+    return request<{{.ResourceName}}>(() => apiClient.post(`{{.RoutePath}}/${id}/${field}`, form, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+      onUploadProgress: (event: { loaded: number; total?: number }) => {
+        if (onProgress && event.total) {
+          onProgress(Math.round((event.loaded * 100) / event.total))
+        }
+      }
+    }), `Failed to upload ${field}`)
+  },
+
+  removeAttachment: (id: number, field: string) =>
+    request<{{.ResourceName}}>(() => apiClient.delete(`{{.RoutePath}}/${id}/${field}`), `Failed to remove ${field}`)
+}
@@ -0,0 +1,62 @@
+// Conversions for date, datetime and time fields. The API sends ISO 8601
+// strings; date inputs use YYYY-MM-DD, datetime-local inputs YYYY-MM-DDTHH:MM
+// in local time and time inputs HH:MM. Dates are stored at midnight UTC.
+
+const pad = (n: number) => String(n).padStart(2, '0')
+
+// Parse an ISO 8601 string from the API; the zero time of an unset Go time
+// reads as empty and times without a zone as UTC
+export function parseISODate(value?: string | null): Date | null {
+  if (!value || value.startsWith('0001-01-01')) {
+    return null
+  }
+  let iso = value.replace(' ', 'T')
+  if (iso.includes('T') && !/(Z|[+-]\d{2}:?\d{2})$/i.test(iso)) {
+    iso += 'Z'
+  }
+  const date = new Date(iso)
+  return Number.isNaN(date.getTime()) ? null : date
+}
+
+export function toDateInput(value?: string | null): string | undefined {
+  return parseISODate(value)?.toISOString().slice(0, 10)
+}
+
+export function fromDateInput(value?: string): string | undefined {
+  return value ? `${value}T00:00:00Z` : undefined
+}
+
+export function toDateTimeInput(value?: string | null): string | undefined {
+  const date = parseISODate(value)
+  if (!date) {
+    return undefined
+  }
+  return `${date.getFullYear()}-${pad(date.getMonth() + 1)}-${pad(date.getDate())}T${pad(date.getHours())}:${pad(date.getMinutes())}`
+}
+
+export function fromDateTimeInput(value?: string): string | undefined {
+  return value ? new Date(value).toISOString() : undefined
+}
+
+export function toTimeInput(value?: string | null): string | undefined {
+  return value ? value.slice(0, 5) : undefined
+}
+
+// Format for display in the user's locale
+export function formatDate(value?: string | null): string {
+  const date = parseISODate(value)
+  return date ? new Intl.DateTimeFormat(undefined, { dateStyle: 'medium', timeZone: 'UTC' }).format(date) : ''
+}
+
+export function formatDateTime(value?: string | null): string {
+  const date = parseISODate(value)
+  return date ? new Intl.DateTimeFormat(undefined, { dateStyle: 'medium', timeStyle: 'short' }).format(date) : ''
+}
+
+export function formatTime(value?: string | null): string {
+  const [hours, minutes] = (value ?? '').split(':').map(Number)
+  if (!value || Number.isNaN(hours) || Number.isNaN(minutes)) {
+    return ''
+  }
+  return new Intl.DateTimeFormat(undefined, { timeStyle: 'short', timeZone: 'UTC' }).format(Date.UTC(1970, 0, 1, hours, minutes))
+}
@@ -0,0 +1,10 @@
+// Formatting for money and decimal fields. The API sends them as strings,
+// which Intl formats without rounding them through a float.
+
+export function formatMoney(value: string | null | undefined, currency: string): string {
+  return value ? new Intl.NumberFormat(undefined, { style: 'currency', currency }).format(value as Intl.StringNumericLiteral) : ''
+}
+
+export function formatDecimal(value: string | null | undefined, scale: number): string {
+  return value ? new Intl.NumberFormat(undefined, { minimumFractionDigits: scale, maximumFractionDigits: scale }).format(value as Intl.StringNumericLiteral) : ''
+}
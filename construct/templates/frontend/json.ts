@@ -0,0 +1,23 @@
+// Conversions for json fields, which the form edits as JSON text. Empty
+// text stands for an empty object.
+
+export function toJSONInput(value?: Record<string, unknown> | null): string {
+  return value && Object.keys(value).length > 0 ? JSON.stringify(value, null, 2) : ''
+}
+
+export function fromJSONInput(value?: string): Record<string, unknown> {
+  return value?.trim() ? JSON.parse(value) : {}
+}
+
+// Whether the text is a JSON object or empty
+export function isJSONObject(value: string): boolean {
+  if (!value.trim()) {
+    return true
+  }
+  try {
+    const parsed = JSON.parse(value)
+    return typeof parsed === 'object' && parsed !== null && !Array.isArray(parsed)
+  } catch {
+    return false
+  }
+}
@@ -0,0 +1,82 @@
+// Responses and errors of the API, as the API modules of every resource
+// read them
+
+// Page of a list, as the list endpoints send it next to the records
+export interface Pagination {
+  total: number
+  page: number
+  page_size: number
+  total_pages: number
+}
+
+// Response of a list endpoint
+export interface Paginated<T> {
+  data: T[]
+  pagination: Pagination
+}
+
+// Record as the /all endpoints list it for selects
+export interface SelectOption {
+  id: number
+  name: string
+}
+
+// A failed request, with the message of the API and the messages of the
+// fields it found invalid, by name. status is 0 when no response came.
+export class ApiError extends Error {
+  constructor(
+    message: string,
+    public readonly status: number,
+    public readonly fieldErrors: Record<string, string> = {}
+  ) {
+    super(message)
+    this.name = 'ApiError'
+  }
+
+  // Whether the API rejected the fields of the request
+  get isValidation(): boolean {
+    return Object.keys(this.fieldErrors).length > 0
+  }
+}
+
+interface ErrorBody {
+  error?: string
+  errors?: Record<string, string>
+}
+
+// Turns what a request threw into an ApiError, using fallback when the API
+// gave no message
+export function toApiError(error: unknown, fallback: string): ApiError {
+  if (error instanceof ApiError) {
+    return error
+  }
+  const response = (error as { response?: { status: number; data?: ErrorBody } } | null)?.response
+  if (response) {
+    return new ApiError(response.data?.error || fallback, response.status, response.data?.errors ?? {})
+  }
+  return new ApiError(error instanceof Error ? error.message : fallback, 0)
+}
+
+// Sends a request and returns the body of its response, throwing an
+// ApiError when it fails
+export async function request<T>(send: () => Promise<{ data: T }>, fallback: string): Promise<T> {
+  try {
+    const response = await send()
+    return response.data
+  } catch (error) {
+    throw toApiError(error, fallback)
+  }
+}
+
+// Query parameters of a list, joining list values with commas and leaving
+// out the ones that are not set
+export function toQuery(params: object): Record<string, string | number | boolean> {
+  const query: Record<string, string | number | boolean> = {}
+  for (const [key, value] of Object.entries(params)) {
+    if (value === undefined || value === null || value === '' || (Array.isArray(value) && value.length === 0)) {
+      continue
+    }
+    query[key] = Array.isArray(value) ? value.join(',') : value
+  }
+  return query
+}
@@ -0,0 +1,26 @@
+// Slugs as the API derives them, previewed by the forms of slug fields
+
+const slugLength = 100
+
+// Lowercase letters and digits of the text joined by dashes, e.g.
+// "Hello, World!" becomes "hello-world"
+export function slugify(text?: string | null): string {
+  const words = (text ?? '').toLowerCase().split(/[^\p{L}\p{N}]+/u).filter(Boolean)
+  return Array.from(words.join('-')).slice(0, slugLength).join('').replace(/-+$/, '')
+}
+
+// Whether the slug is base, or base numbered to tell it from the slug of
+// another record, e.g. hello-world-2
+export function slugHasBase(slug: string, base: string): boolean {
+  return slug === base || (slug.startsWith(base + '-') && /^\d+$/.test(slug.slice(base.length + 1)))
+}
+
+// The slug the API gives a record for text. The current slug of a record
+// is kept while the text still gives it.
+export function previewSlug(text?: string | null, current?: string): string {
+  const base = slugify(text)
+  if (current && (!base || slugHasBase(current, base))) {
+    return current
+  }
+  return base
+}
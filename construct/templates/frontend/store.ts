@@ -1,59 +1,106 @@
 import { defineStore } from 'pinia'
 import { ref, computed } from 'vue'
-import { use{{.PluralName}} } from '../composables/use{{.PluralName}}'
-import type { {{.ResourceName}}, {{.ResourceName}}CreateRequest, {{.ResourceName}}UpdateRequest, QueryParams } from '../types'
+import { useRouter, type LocationQuery } from 'vue-router'
+import { toApiError, type ApiError, type Pagination } from '~/utils/requests'
+import { {{.LowerPluralName}}Api } from '../api/{{.LowerPluralName}}'
+import type { {{.ResourceName}}, {{.ResourceName}}CreateRequest, {{.ResourceName}}UpdateRequest, {{.ResourceName}}QueryParams } from '../types/{{.LowerResourceName}}'
+
+// Filter parameters of the list endpoint, with the label of their chip and
+// the type their values are read as from the URL
+const filterParams: Record<string, { label: string; value: 'string' | 'number' | 'boolean' | 'string[]' | 'number[]' }> = {
+  // construct:fields filters
+  {{range .Fields}}{{range .FilterParams}}{{.Name}}: { label: '{{.Label}}', value: '{{.Value}}' },
+  {{end}}{{end}}// construct:end
+}
+
+// Delay before a search is sent, so typing sends one request
+const searchDelay = 300
+
+// queryFromRoute reads the list parameters from a URL query string
+const queryFromRoute = (route: LocationQuery): {{.ResourceName}}QueryParams => {
+  const query: Record<string, unknown> = {}
+  for (const [key, raw] of Object.entries(route)) {
+    const text = Array.isArray(raw) ? raw.join(',') : raw
+    if (!text) {
+      continue
+    }
+    const value = key === 'page' || key === 'limit' ? 'number' : filterParams[key]?.value ?? 'string'
+    if (value === 'number') {
+      query[key] = Number(text)
+    } else if (value === 'boolean') {
+      query[key] = text === 'true'
+    } else if (value === 'number[]') {
+      query[key] = text.split(',').map(Number)
+    } else if (value === 'string[]') {
+      query[key] = text.split(',')
+    } else {
+      query[key] = text
+    }
+  }
+  return query as {{.ResourceName}}QueryParams
+}
+
+// queryToRoute writes the list parameters to a URL query string, leaving out
+// the ones that are not set
+const queryToRoute = (query: {{.ResourceName}}QueryParams): Record<string, string> => {
+  const route: Record<string, string> = {}
+  for (const [key, value] of Object.entries(query)) {
+    if (value !== undefined && value !== '' && !(Array.isArray(value) && value.length === 0)) {
+      route[key] = Array.isArray(value) ? value.join(',') : String(value)
+    }
+  }
+  return route
+}
 
 export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () => {
-  // Get the composable with API operations
-  const {{.LowerPluralName}}Api = use{{.PluralName}}()
+  const router = useRouter()
 
   // State
   const {{.LowerPluralName}} = ref<{{.ResourceName}}[]>([])
   const selected{{.ResourceName}} = ref<{{.ResourceName}} | null>(null)
   const loading = ref(false)
-  const error = ref<string | null>(null)
-  const pagination = ref({
+  const error = ref<ApiError | null>(null)
+  const pagination = ref<Pagination>({
     total: 0,
     page: 1,
     page_size: 10,
     total_pages: 1
   })
 
-  // Search
+  // Page, sorting, search and filters of the list, kept in the URL
+  const query = ref<{{.ResourceName}}QueryParams>({ page: 1, limit: 10 })
+
+  // Text of the search box, sent as q once typing pauses
   const searchQuery = ref('')
+  let searchTimer: ReturnType<typeof setTimeout> | undefined
 
   // Getters
   const total{{.PluralName}} = computed(() => pagination.value.total)
   const has{{.PluralName}} = computed(() => {{.LowerPluralName}}.value.length > 0)
   const isLoading = computed(() => loading.value)
 
-  // Filtered items based on search
-  const filtered{{.PluralName}} = computed(() => {
-    let filtered = {{.LowerPluralName}}.value
-
-    if (searchQuery.value) {
-      const query = searchQuery.value.toLowerCase()
-      filtered = filtered.filter((item: {{.ResourceName}}) => {
-        // Search across all string fields
-        {{range .Fields}}{{if eq .TypeScriptType "string"}}if (item.{{.Name}} && item.{{.Name}}.toLowerCase().includes(query)) return true
-        {{end}}{{end}}return false
-      })
-    }
-
-    return filtered
+  // Filters that are set, shown as chips that remove them
+  const filterChips = computed(() => {
+    return Object.entries(query.value)
+      .filter(([param, value]) => param in filterParams && value !== undefined && value !== '' && !(Array.isArray(value) && value.length === 0))
+      .map(([param, value]) => ({
+        param: param as keyof {{.ResourceName}}QueryParams,
+        label: filterParams[param].label,
+        value: Array.isArray(value) ? value.join(', ') : String(value)
+      }))
   })
 
-  // Actions - using the composable internally
-  const fetch{{.PluralName}} = async (params?: QueryParams): Promise<void> => {
+  // Actions - requests failing with an ApiError leave it in error
+  const fetch{{.PluralName}} = async (): Promise<void> => {
     loading.value = true
     error.value = null
 
     try {
-      const result = await {{.LowerPluralName}}Api.fetch{{.PluralName}}(params)
-      {{.LowerPluralName}}.value = result.{{.LowerPluralName}}
+      const result = await {{.LowerPluralName}}Api.list(query.value)
+      {{.LowerPluralName}}.value = result.data ?? []
       pagination.value = result.pagination
     } catch (err: unknown) {
-      error.value = err instanceof Error ? err.message : 'Failed to fetch {{.LowerPluralName}}'
+      error.value = toApiError(err, 'Failed to fetch {{.HumanPluralName}}')
     } finally {
       loading.value = false
     }
@@ -64,11 +111,11 @@ export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () =>
     error.value = null
 
     try {
-      const item = await {{.LowerPluralName}}Api.fetch{{.ResourceName}}(id)
+      const item = await {{.LowerPluralName}}Api.get(id)
       selected{{.ResourceName}}.value = item
       return item
     } catch (err: unknown) {
-      error.value = err instanceof Error ? err.message : 'Failed to fetch {{.LowerResourceName}}'
+      error.value = toApiError(err, 'Failed to fetch {{.HumanName}}')
       return null
     } finally {
       loading.value = false
@@ -80,12 +127,12 @@ export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () =>
     error.value = null
 
     try {
-      const newItem = await {{.LowerPluralName}}Api.create{{.ResourceName}}(data)
+      const newItem = await {{.LowerPluralName}}Api.create(data)
       {{.LowerPluralName}}.value.push(newItem)
       pagination.value.total += 1
       return newItem
     } catch (err: unknown) {
-      error.value = err instanceof Error ? err.message : 'Failed to create {{.LowerResourceName}}'
+      error.value = toApiError(err, 'Failed to create {{.HumanName}}')
       return null
     } finally {
       loading.value = false
@@ -97,7 +144,7 @@ export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () =>
     error.value = null
 
     try {
-      const updatedItem = await {{.LowerPluralName}}Api.update{{.ResourceName}}(id, data)
+      const updatedItem = await {{.LowerPluralName}}Api.update(id, data)
       const index = {{.LowerPluralName}}.value.findIndex(item => item.id === id)
       if (index !== -1) {
         {{.LowerPluralName}}.value[index] = updatedItem
@@ -107,7 +154,7 @@ export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () =>
       }
       return updatedItem
     } catch (err: unknown) {
-      error.value = err instanceof Error ? err.message : 'Failed to update {{.LowerResourceName}}'
+      error.value = toApiError(err, 'Failed to update {{.HumanName}}')
       return null
     } finally {
       loading.value = false
@@ -119,7 +166,7 @@ export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () =>
     error.value = null
 
     try {
-      await {{.LowerPluralName}}Api.delete{{.ResourceName}}(id)
+      await {{.LowerPluralName}}Api.delete(id)
       {{.LowerPluralName}}.value = {{.LowerPluralName}}.value.filter(item => item.id !== id)
       if (selected{{.ResourceName}}.value?.id === id) {
         selected{{.ResourceName}}.value = null
@@ -127,24 +174,76 @@ export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () =>
       pagination.value.total -= 1
       return true
     } catch (err: unknown) {
-      error.value = err instanceof Error ? err.message : 'Failed to delete {{.LowerResourceName}}'
+      error.value = toApiError(err, 'Failed to delete {{.HumanName}}')
       return false
     } finally {
       loading.value = false
     }
   }
 
+  // Uploads a file to an attachment field, or removes the current one when
+  // file is null
+  const saveAttachment = async (id: number, field: string, file: File | null, onProgress?: (percent: number) => void): Promise<{{.ResourceName}} | null> => {
+    error.value = null
+
+    try {
+      const updatedItem = file
+        ? await {{.LowerPluralName}}Api.uploadAttachment(id, field, file, onProgress)
+        : await {{.LowerPluralName}}Api.removeAttachment(id, field)
+      const index = {{.LowerPluralName}}.value.findIndex(item => item.id === id)
+      if (index !== -1) {
+        {{.LowerPluralName}}.value[index] = updatedItem
+      }
+      if (selected{{.ResourceName}}.value?.id === id) {
+        selected{{.ResourceName}}.value = updatedItem
+      }
+      return updatedItem
+    } catch (err: unknown) {
+      error.value = toApiError(err, `Failed to save ${field}`)
+      return null
+    }
+  }
+
+  // Reads the query from the URL, when the page is opened or reloaded
+  const loadQuery = () => {
+    query.value = { page: 1, limit: 10, ...queryFromRoute(router.currentRoute.value.query) }
+    searchQuery.value = query.value.q ?? ''
+  }
+
+  // Changes the query, writes it to the URL and fetches the matching page
+  const applyQuery = async (changes: Partial<{{.ResourceName}}QueryParams>): Promise<void> => {
+    query.value = { ...query.value, ...changes }
+    await router.replace({ query: queryToRoute(query.value) })
+    await fetch{{.PluralName}}()
+  }
+
   // Helper actions
-  const setSearchQuery = (query: string) => {
-    searchQuery.value = query
+  const setSearchQuery = (text: string) => {
+    searchQuery.value = text
+    clearTimeout(searchTimer)
+    searchTimer = setTimeout(() => {
+      applyQuery({ q: text || undefined, page: 1 })
+    }, searchDelay)
+  }
+
+  const setSort = async (sort: string, order: 'asc' | 'desc'): Promise<void> => {
+    await applyQuery({ sort, order })
+  }
+
+  const setFilter = async <K extends keyof {{.ResourceName}}QueryParams>(param: K, value: {{.ResourceName}}QueryParams[K]): Promise<void> => {
+    await applyQuery({ [param]: value, page: 1 })
+  }
+
+  const removeFilter = async (param: keyof {{.ResourceName}}QueryParams): Promise<void> => {
+    await applyQuery({ [param]: undefined, page: 1 })
   }
 
   const setPage = async (page: number): Promise<void> => {
-    await fetch{{.PluralName}}({ page, page_size: pagination.value.page_size })
+    await applyQuery({ page })
   }
 
   const setPerPage = async (perPage: number): Promise<void> => {
-    await fetch{{.PluralName}}({ page: 1, page_size: perPage })
+    await applyQuery({ page: 1, limit: perPage })
   }
 
   const clearError = () => {
@@ -155,8 +254,11 @@ export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () =>
     selected{{.ResourceName}}.value = null
   }
 
-  const clearFilters = () => {
+  const clearFilters = async (): Promise<void> => {
+    clearTimeout(searchTimer)
     searchQuery.value = ''
+    query.value = { limit: query.value.limit, sort: query.value.sort, order: query.value.order }
+    await applyQuery({ page: 1 })
   }
 
   return {
@@ -166,13 +268,14 @@ export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () =>
     loading,
     error,
     pagination,
+    query,
     searchQuery,
 
     // Getters
     total{{.PluralName}},
     has{{.PluralName}},
     isLoading,
-    filtered{{.PluralName}},
+    filterChips,
 
     // Actions
     fetch{{.PluralName}},
@@ -180,7 +283,12 @@ export const use{{.PluralName}}Store = defineStore('{{.LowerPluralName}}', () =>
     create{{.ResourceName}},
     update{{.ResourceName}},
     delete{{.ResourceName}},
+    saveAttachment,
+    loadQuery,
     setSearchQuery,
+    setSort,
+    setFilter,
+    removeFilter,
     setPage,
     setPerPage,
     clearError,
@@ -1,16 +1,37 @@
-export interface {{.ResourceName}} {
+{{if .DisplayField}}// Displayed by {{.DisplayField}}
+{{end}}export interface {{.ResourceName}} {
   id: number
-  {{range .Fields}}{{.Name}}: {{.TypeScriptType}}
-  {{end}}created_at: string
+  // construct:fields model
+  {{range .Fields}}{{if eq .Relationship "many_to_many"}}{{.RelatedName}}?: {{.RelatedType}}[]
+  {{else if .IsAttachment}}{{.Name}}?: {{.TypeScriptType}} | null
+  {{else}}{{.Name}}: {{.TypeScriptType}}{{if .IsNullable}} | null{{end}}{{if .IsSlug}} // Derived from {{.SlugSource}}{{end}}
+  {{if eq .Relationship "belongs_to"}}{{.RelatedName}}?: {{.RelatedType}}
+  {{end}}{{end}}{{end}}// construct:end
+  created_at: string
   updated_at: string
 }
 
 export interface {{.ResourceName}}CreateRequest {
-  {{range .Fields}}{{.Name}}: {{.TypeScriptType}}
-  {{end}}
+  // construct:fields create
+  {{range .Fields}}{{if not (or .IsAttachment .IsSlug)}}{{.Name}}{{if or .IsNullable .HasDefault}}?{{end}}: {{.TypeScriptType}}{{if .IsNullable}} | null{{end}}
+  {{end}}{{end}}// construct:end
 }
 
 export interface {{.ResourceName}}UpdateRequest {
-  {{range .Fields}}{{.Name}}?: {{.TypeScriptType}}
-  {{end}}
+  // construct:fields update
+  {{range .Fields}}{{if not (or .IsAttachment .IsSlug)}}{{.Name}}?: {{.TypeScriptType}}{{if .IsNullable}} | null{{end}}
+  {{end}}{{end}}// construct:end
+}
+
+// Query parameters of the list endpoint, each filter shown as a chip on the
+// index page
+export interface {{.ResourceName}}QueryParams {
+  page?: number
+  limit?: number
+  sort?: string
+  order?: 'asc' | 'desc'
+  q?: string
+  // construct:fields filters
+  {{range .Fields}}{{range .FilterParams}}{{.Name}}?: {{.TypeScriptType}}
+  {{end}}{{end}}// construct:end
 }
\ No newline at end of file
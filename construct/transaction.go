@@ -0,0 +1,69 @@
+package construct
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// fileTransaction records the original content of every file it touches so
+// a failed change can be undone, leaving the project as it was
+type fileTransaction struct {
+	root     string
+	paths    []string
+	original map[string][]byte // nil when the file did not exist
+}
+
+func newFileTransaction(root string) *fileTransaction {
+	return &fileTransaction{root: root, original: map[string][]byte{}}
+}
+
+// track remembers the content of a file before it is first changed
+func (t *fileTransaction) track(path string) error {
+	if _, ok := t.original[path]; ok {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	t.paths = append(t.paths, path)
+	t.original[path] = content
+	return nil
+}
+
+// write writes a file, creating its directory
+func (t *fileTransaction) write(path string, content []byte) error {
+	if err := t.track(path); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// remove removes a file
+func (t *fileTransaction) remove(path string) error {
+	if err := t.track(path); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// rollback restores every tracked file, in reverse order
+func (t *fileTransaction) rollback() {
+	for i := len(t.paths) - 1; i >= 0; i-- {
+		path := t.paths[i]
+		content := t.original[path]
+		if content == nil {
+			os.Remove(path)
+			removeEmptyDirs(filepath.Dir(path), t.root)
+			continue
+		}
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, content, 0644)
+	}
+}
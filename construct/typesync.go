@@ -0,0 +1,765 @@
+package construct
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/base-go/mamba"
+	"golang.org/x/tools/go/packages"
+)
+
+var typesSyncCmd = &mamba.Command{
+	Use:   "types:sync [package...]",
+	Short: "Generate TypeScript interfaces from the Go models",
+	Long: `Generate TypeScript interfaces for the structs of Go packages.
+
+The packages, api/models and app/models by default, are loaded with the Go
+type checker, and each is written to vue/app/types/<package dir>.ts, e.g.
+vue/app/types/api/models.ts. Fields follow encoding/json: their json names,
+omitempty as optional, pointers, slices and maps as "| null", time.Time as
+a string and the fields of embedded structs. Named string and number types
+with constants become unions of their values. The files are overwritten
+every time, edits to them are lost.
+
+Examples:
+  construct types:sync
+  construct types:sync --zod            # Also write zod schemas
+  construct types:sync --check          # Fail when the files are stale
+  construct types:sync ./core/types     # Other packages`,
+	Run: func(cmd *mamba.Command, args []string) {
+		args = parseFlags(cmd, args)
+
+		var opts GenerateOptions
+		opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		opts.Diff, _ = cmd.Flags().GetBool("diff")
+		check, _ := cmd.Flags().GetBool("check")
+		zod, _ := cmd.Flags().GetBool("zod")
+		runTypesSync(args, check, zod, opts)
+	},
+}
+
+func init() {
+	typesSyncCmd.Flags().Bool("check", false, "exit with an error when the files are out of date, without writing them")
+	typesSyncCmd.Flags().Bool("zod", false, "also write a zod schema for every type")
+	typesSyncCmd.Flags().Bool("dry-run", false, "list the files that would be created, modified or skipped")
+	typesSyncCmd.Flags().Bool("diff", false, "show a unified diff of every change (implies --dry-run)")
+}
+
+// modelPackages are the packages types:sync reads by default, when they exist
+var modelPackages = []string{"api/models", "app/models"}
+
+func runTypesSync(patterns []string, check, zod bool, opts GenerateOptions) {
+	printBanner()
+
+	root, err := findProjectRoot()
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	files, err := typeFiles(root, patterns, zod)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if check {
+		command := "construct types:sync"
+		if zod {
+			command += " --zod"
+		}
+		stale := 0
+		for _, f := range files {
+			current, err := os.ReadFile(f.Path)
+			if err == nil && bytes.Equal(current, f.Content) {
+				fmt.Printf("  ✓ %s is up to date\n", f.Rel)
+				continue
+			}
+			fmt.Printf("  ⚠️  %s is out of date\n", f.Rel)
+			stale++
+		}
+		if stale > 0 {
+			fmt.Printf("\n❌ Error: %d file(s) are out of date, run %s\n", stale, command)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := writeFiles(root, files, opts); err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !opts.preview() {
+		fmt.Println()
+		fmt.Println("🎉 Types synced")
+	}
+}
+
+// typeFiles loads the packages and renders the TypeScript file of each
+func typeFiles(root string, patterns []string, zod bool) ([]*generatedFile, error) {
+	if len(patterns) == 0 {
+		for _, dir := range modelPackages {
+			if info, err := os.Stat(filepath.Join(root, dir)); err == nil && info.IsDir() {
+				patterns = append(patterns, "./"+dir)
+			}
+		}
+		if len(patterns) == 0 {
+			return nil, fmt.Errorf("no Go models found in %s", strings.Join(modelPackages, " or "))
+		}
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:  root,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", strings.Join(patterns, " "), err)
+	}
+	var problems []string
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			// go list repeats the errors the type checker reports
+			if e.Kind == packages.ListError && len(pkg.Errors) > 1 {
+				continue
+			}
+			if e.Pos == "" || e.Pos == "-" {
+				problems = append(problems, "     "+e.Msg)
+			} else {
+				problems = append(problems, "     "+displayPath(root, e.Pos)+": "+e.Msg)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("failed to load the Go packages:\n%s", strings.Join(problems, "\n"))
+	}
+
+	// Each package is written to a file named after its directory
+	dirs := map[string]string{}
+	for _, pkg := range pkgs {
+		if len(pkg.GoFiles) == 0 {
+			return nil, fmt.Errorf("%s has no Go files", pkg.PkgPath)
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(pkg.GoFiles[0]))
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("%s is outside the project", pkg.PkgPath)
+		}
+		dirs[pkg.PkgPath] = filepath.ToSlash(rel)
+	}
+
+	var files []*generatedFile
+	for _, pkg := range pkgs {
+		dir := dirs[pkg.PkgPath]
+		content := newTypeWriter(pkg, dirs, zod).render()
+		f := newGeneratedFile(root, filepath.Join(root, "vue", "app", "types", filepath.FromSlash(dir)+".ts"), []byte(content))
+		f.Template, f.TemplateVersion = dir, templateVersion(content)
+		f.Derived = true
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// typeWriter renders the exported types of a package as TypeScript
+type typeWriter struct {
+	pkg  *packages.Package
+	dirs map[string]string // Directories of the synced packages, by import path
+	zod  bool
+
+	comments map[*types.Var]string // Comments of the struct fields of the package
+	names    map[*types.TypeName]string
+	pending  []*types.TypeName          // Structs of other packages to render in this file
+	imports  map[string]map[string]bool // Names imported from the files of other synced packages, true for types
+}
+
+func newTypeWriter(pkg *packages.Package, dirs map[string]string, zod bool) *typeWriter {
+	w := &typeWriter{
+		pkg:      pkg,
+		dirs:     dirs,
+		zod:      zod,
+		comments: map[*types.Var]string{},
+		names:    map[*types.TypeName]string{},
+		imports:  map[string]map[string]bool{},
+	}
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			field, ok := n.(*ast.Field)
+			if !ok {
+				return true
+			}
+			comment := field.Doc.Text()
+			if comment == "" {
+				comment = field.Comment.Text()
+			}
+			for _, name := range field.Names {
+				if v, ok := pkg.TypesInfo.Defs[name].(*types.Var); ok && comment != "" {
+					w.comments[v] = oneLine(comment)
+				}
+			}
+			return true
+		})
+	}
+	return w
+}
+
+// declaredType is an exported, non-generic type of the package with its doc
+type declaredType struct {
+	obj *types.TypeName
+	doc string
+}
+
+// declaredTypes lists the types of the package in the order of its files
+func (w *typeWriter) declaredTypes() []declaredType {
+	var declared []declaredType
+	for _, file := range w.pkg.Syntax {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || !ts.Name.IsExported() || ts.TypeParams != nil {
+					continue
+				}
+				obj, ok := w.pkg.TypesInfo.Defs[ts.Name].(*types.TypeName)
+				if !ok {
+					continue
+				}
+				doc := ts.Doc.Text()
+				if doc == "" && len(gen.Specs) == 1 {
+					doc = gen.Doc.Text()
+				}
+				declared = append(declared, declaredType{obj: obj, doc: doc})
+				w.names[obj] = obj.Name()
+			}
+		}
+	}
+	return declared
+}
+
+// render renders the TypeScript file of the package
+func (w *typeWriter) render() string {
+	var body strings.Builder
+	for _, d := range w.declaredTypes() {
+		body.WriteString("\n")
+		body.WriteString(w.renderType(d.obj, d.doc))
+	}
+	// Structs of other packages are rendered as they are found
+	for len(w.pending) > 0 {
+		obj := w.pending[0]
+		w.pending = w.pending[1:]
+		body.WriteString("\n")
+		body.WriteString(w.renderType(obj, ""))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by construct types:sync from %s, do not edit: run it again to update\n", w.dirs[w.pkg.PkgPath])
+	if w.zod || len(w.imports) > 0 {
+		b.WriteString("\n")
+	}
+	if w.zod {
+		b.WriteString("import * as z from 'zod'\n")
+	}
+	paths := make([]string, 0, len(w.imports))
+	for path := range w.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		var names []string
+		for name := range w.imports[path] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		keyword := "import type"
+		if w.zod {
+			// Schemas are values, next to the types they validate
+			keyword = "import"
+			for i, name := range names {
+				if w.imports[path][name] {
+					names[i] = "type " + name
+				}
+			}
+		}
+		fmt.Fprintf(&b, "%s { %s } from '%s'\n", keyword, strings.Join(names, ", "), importPath(w.dirs[w.pkg.PkgPath], path))
+	}
+	b.WriteString(body.String())
+	return b.String()
+}
+
+// importPath returns the path the file of a package imports the file of
+// another by, given their directories
+func importPath(from, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+	if err != nil {
+		return to
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// renderType renders a named type as an interface when it is a struct, and
+// as a type otherwise, followed by its zod schema
+func (w *typeWriter) renderType(obj *types.TypeName, doc string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(doc), "\n") {
+		if line != "" {
+			fmt.Fprintf(&b, "// %s\n", line)
+		}
+	}
+	name := w.names[obj]
+	if st, ok := obj.Type().Underlying().(*types.Struct); ok && !marshalsItself(obj.Type()) {
+		fields := w.structFields(st)
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+		for _, f := range fields {
+			fmt.Fprintf(&b, "  %s\n", f.ts)
+		}
+		b.WriteString("}\n")
+		if w.zod {
+			// Annotated, since the schemas of recursive structs cannot be inferred
+			fmt.Fprintf(&b, "\nexport const %sSchema: z.ZodType<%s> = z.object({\n", name, name)
+			for _, f := range fields {
+				fmt.Fprintf(&b, "  %s,\n", f.zod)
+			}
+			b.WriteString("})\n")
+		}
+		return b.String()
+	}
+
+	t := w.enumType(obj)
+	switch {
+	case t != nil:
+	case marshalsItself(obj.Type()):
+		t = marshaledType(obj.Type())
+	default:
+		t = w.typeOf(obj.Type().Underlying())
+	}
+	fmt.Fprintf(&b, "export type %s = %s\n", name, t.ts)
+	if w.zod {
+		fmt.Fprintf(&b, "\nexport const %sSchema = %s\n", name, t.zod)
+	}
+	return b.String()
+}
+
+// tsType is a TypeScript type and the zod schema validating it
+type tsType struct {
+	ts  string
+	zod string
+}
+
+var unknownType = &tsType{"unknown", "z.unknown()"}
+
+// renderedField is a property of an interface and of its zod schema
+type renderedField struct {
+	ts  string
+	zod string
+}
+
+// jsonField is a field of a struct as encoding/json marshals it
+type jsonField struct {
+	name     string
+	v        *types.Var
+	optional bool
+	quoted   bool // The ,string option
+	depth    int  // Depth of embedding
+	tagged   bool
+}
+
+// structFields renders the fields of a struct as encoding/json marshals
+// them, promoting the fields of embedded structs
+func (w *typeWriter) structFields(st *types.Struct) []renderedField {
+	var fields []jsonField
+	w.collectFields(st, 0, false, map[*types.Struct]bool{}, &fields)
+
+	// A field hides the fields of the same name embedded deeper, and a
+	// tagged field the untagged ones at its depth; others at the same
+	// depth cancel each other out
+	byName := map[string][]jsonField{}
+	var order []string
+	for _, f := range fields {
+		if _, ok := byName[f.name]; !ok {
+			order = append(order, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	var rendered []renderedField
+	for _, name := range order {
+		candidates := byName[name]
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].depth < candidates[j].depth })
+		top := candidates[:1]
+		for _, f := range candidates[1:] {
+			if f.depth == top[0].depth {
+				top = append(top, f)
+			}
+		}
+		if len(top) > 1 {
+			var tagged []jsonField
+			for _, f := range top {
+				if f.tagged {
+					tagged = append(tagged, f)
+				}
+			}
+			if len(tagged) != 1 {
+				continue
+			}
+			top = tagged
+		}
+		rendered = append(rendered, w.renderField(top[0]))
+	}
+	return rendered
+}
+
+// collectFields lists the fields of a struct and, for embedded structs
+// without a json name, their fields one level deeper
+func (w *typeWriter) collectFields(st *types.Struct, depth int, optional bool, seen map[*types.Struct]bool, fields *[]jsonField) {
+	if seen[st] {
+		return
+	}
+	seen[st] = true
+	defer delete(seen, st)
+
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i)).Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if v.Embedded() && name == "" {
+			t := v.Type()
+			pointer := false
+			if p, ok := t.(*types.Pointer); ok {
+				t, pointer = p.Elem(), true
+			}
+			if embedded, ok := t.Underlying().(*types.Struct); ok && !marshalsItself(t) {
+				w.collectFields(embedded, depth+1, optional || pointer, seen, fields)
+				continue
+			}
+			if !v.Exported() {
+				continue
+			}
+		} else if !v.Exported() {
+			continue
+		}
+
+		if name == "" {
+			name = v.Name()
+		}
+		*fields = append(*fields, jsonField{
+			name:     name,
+			v:        v,
+			optional: optional || hasTagOption(options, "omitempty") || hasTagOption(options, "omitzero"),
+			quoted:   hasTagOption(options, "string"),
+			depth:    depth,
+			tagged:   tag != "",
+		})
+	}
+}
+
+// hasTagOption reports whether the options of a json tag include one
+func hasTagOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// renderField renders a field as a property of an interface and a schema
+func (w *typeWriter) renderField(f jsonField) renderedField {
+	// A nil slice or map is left out with omitempty, and null otherwise
+	t := w.valueOf(f.v.Type())
+	if f.optional {
+		t = w.typeOf(f.v.Type())
+	}
+	if f.quoted {
+		switch f.v.Type().Underlying().(type) {
+		case *types.Basic:
+			t = &tsType{"string", "z.string()"}
+		}
+	}
+	name := propertyName(f.name)
+	ts := name + ": " + t.ts
+	zod := name + ": " + t.zod
+	if f.optional {
+		ts = name + "?: " + t.ts
+		zod += ".optional()"
+	}
+	if comment := w.comments[f.v]; comment != "" {
+		ts += " // " + comment
+	}
+	return renderedField{ts: ts, zod: zod}
+}
+
+// valueOf maps a Go type to the TypeScript type of its JSON, including the
+// null of a nil slice or map
+func (w *typeWriter) valueOf(t types.Type) *tsType {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		if !marshalsItself(t) {
+			return nullable(w.typeOf(t))
+		}
+	}
+	return w.typeOf(t)
+}
+
+// typeOf maps a Go type to the TypeScript type of its JSON when it is not
+// nil
+func (w *typeWriter) typeOf(t types.Type) *tsType {
+	t = types.Unalias(t)
+	switch t := t.(type) {
+	case *types.Named:
+		return w.namedType(t)
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return &tsType{"boolean", "z.boolean()"}
+		case t.Info()&types.IsNumeric != 0:
+			return &tsType{"number", "z.number()"}
+		case t.Info()&types.IsString != 0:
+			return &tsType{"string", "z.string()"}
+		}
+	case *types.Pointer:
+		return nullable(w.valueOf(t.Elem()))
+	case *types.Slice:
+		if isByte(t.Elem()) {
+			return &tsType{"string", "z.string()"} // base64
+		}
+		return arrayOf(w.valueOf(t.Elem()))
+	case *types.Array:
+		if isByte(t.Elem()) {
+			return arrayOf(&tsType{"number", "z.number()"})
+		}
+		return arrayOf(w.valueOf(t.Elem()))
+	case *types.Map:
+		value := w.valueOf(t.Elem())
+		return &tsType{"Record<string, " + value.ts + ">", "z.record(z.string(), " + value.zod + ")"}
+	case *types.Struct:
+		var ts, zod []string
+		for _, f := range w.structFields(t) {
+			ts = append(ts, f.ts)
+			zod = append(zod, f.zod)
+		}
+		for i, field := range ts {
+			ts[i], _, _ = strings.Cut(field, " // ")
+		}
+		return &tsType{objectType(ts), "z.object({ " + strings.Join(zod, ", ") + " })"}
+	}
+	return unknownType
+}
+
+// namedType maps a named type: well-known types by what they marshal to,
+// the types of the synced packages by name, and the structs of other
+// packages by an interface rendered in this file
+func (w *typeWriter) namedType(t *types.Named) *tsType {
+	obj := t.Obj()
+	switch qualifiedName(obj) {
+	case "time.Time":
+		return &tsType{"string", "z.string()"}
+	case "gorm.io/gorm.DeletedAt", "database/sql.NullTime":
+		return &tsType{"string | null", "z.string().nullable()"}
+	case "encoding/json.RawMessage":
+		return unknownType
+	case "encoding/json.Number":
+		return &tsType{"number", "z.number()"}
+	}
+
+	// Nullable[T] of models/nullable.go marshals to its value or null
+	if obj.Name() == "Nullable" && t.TypeArgs().Len() == 1 {
+		return nullable(w.typeOf(t.TypeArgs().At(0)))
+	}
+	if t.TypeArgs().Len() == 0 && obj.Pkg() != nil {
+		if obj.Pkg().Path() == w.pkg.PkgPath {
+			if name, ok := w.names[obj]; ok {
+				return reference(name)
+			}
+		} else if _, ok := w.dirs[obj.Pkg().Path()]; ok && obj.Exported() {
+			return w.imported(obj)
+		}
+	}
+	if marshalsItself(t) {
+		return marshaledType(t)
+	}
+	if t.TypeArgs().Len() > 0 {
+		return w.typeOf(t.Underlying())
+	}
+	if _, ok := t.Underlying().(*types.Struct); ok && obj.Pkg() != nil {
+		return reference(w.external(obj))
+	}
+	return w.typeOf(t.Underlying())
+}
+
+// imported refers to a type of another synced package, importing it from
+// that package's file, under an alias after its directory when this
+// package has the same name, e.g. APIModelsPost
+func (w *typeWriter) imported(obj *types.TypeName) *tsType {
+	path := w.dirs[obj.Pkg().Path()]
+	if w.imports[path] == nil {
+		w.imports[path] = map[string]bool{}
+	}
+	name := obj.Name()
+	alias := name
+	if w.pkg.Types.Scope().Lookup(name) != nil {
+		alias = toPascalCase(strings.ReplaceAll(path, "/", "_")) + name
+	}
+	imports := [][2]string{{name, alias}}
+	if w.zod {
+		imports = append(imports, [2]string{name + "Schema", alias + "Schema"})
+	}
+	for i, names := range imports {
+		if names[0] != names[1] {
+			names[0] += " as " + names[1]
+		}
+		w.imports[path][names[0]] = i == 0
+	}
+	return reference(alias)
+}
+
+// external names a struct of another package that is rendered in this file,
+// queuing it the first time
+func (w *typeWriter) external(obj *types.TypeName) string {
+	if name, ok := w.names[obj]; ok {
+		return name
+	}
+	name := obj.Name()
+	taken := w.pkg.Types.Scope().Lookup(name) != nil
+	for other, n := range w.names {
+		if n == name && other != obj {
+			taken = true
+		}
+	}
+	if taken {
+		name = toPascalCase(obj.Pkg().Name()) + name
+	}
+	w.names[obj] = name
+	w.pending = append(w.pending, obj)
+	return name
+}
+
+// enumType returns the union of the values of the constants of a named
+// string or number type, or nil when the package declares none
+func (w *typeWriter) enumType(obj *types.TypeName) *tsType {
+	scope := w.pkg.Types.Scope()
+	var literals []string
+	var values []constant.Value
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), obj.Type()) {
+			continue
+		}
+		values = append(values, c.Val())
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].ExactString() < values[j].ExactString() })
+
+	strs := true
+	var zod []string
+	for _, v := range values {
+		var literal string
+		if v.Kind() == constant.String {
+			literal = tsLiteral("string", constant.StringVal(v))
+		} else {
+			literal, strs = v.ExactString(), false
+		}
+		if containsString(literals, literal) {
+			continue
+		}
+		literals = append(literals, literal)
+		zod = append(zod, "z.literal("+literal+")")
+	}
+	if strs {
+		return &tsType{strings.Join(literals, " | "), "z.enum([" + strings.Join(literals, ", ") + "])"}
+	}
+	if len(zod) == 1 {
+		return &tsType{literals[0], zod[0]}
+	}
+	return &tsType{strings.Join(literals, " | "), "z.union([" + strings.Join(zod, ", ") + "])"}
+}
+
+// marshaledType is the type of the JSON of a type marshaling itself: a
+// string for text and times, and unknown otherwise
+func marshaledType(t types.Type) *tsType {
+	if hasMethod(t, "MarshalText") || embedsTime(t) {
+		return &tsType{"string", "z.string()"}
+	}
+	return unknownType
+}
+
+// marshalsItself reports whether a type implements json.Marshaler or
+// encoding.TextMarshaler
+func marshalsItself(t types.Type) bool {
+	return hasMethod(t, "MarshalJSON") || hasMethod(t, "MarshalText")
+}
+
+// hasMethod reports whether a type or a pointer to it has the method
+func hasMethod(t types.Type, name string) bool {
+	if _, ok := t.Underlying().(*types.Interface); ok {
+		return false
+	}
+	return types.NewMethodSet(types.NewPointer(t)).Lookup(nil, name) != nil
+}
+
+// embedsTime reports whether a struct wraps a time.Time, such as the
+// DateTime of the project's core types
+func embedsTime(t types.Type) bool {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if named, ok := types.Unalias(st.Field(i).Type()).(*types.Named); ok && qualifiedName(named.Obj()) == "time.Time" {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedName returns the import path and name of a type
+func qualifiedName(obj *types.TypeName) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+func isByte(t types.Type) bool {
+	b, ok := types.Unalias(t).(*types.Basic)
+	return ok && b.Kind() == types.Byte
+}
+
+func nullable(t *tsType) *tsType {
+	if strings.HasSuffix(t.ts, "| null") {
+		return t
+	}
+	return &tsType{t.ts + " | null", t.zod + ".nullable()"}
+}
+
+func arrayOf(t *tsType) *tsType {
+	ts := t.ts
+	if strings.ContainsAny(ts, " ") && !strings.HasPrefix(ts, "{") && !strings.HasPrefix(ts, "Record<") {
+		ts = "(" + ts + ")"
+	}
+	return &tsType{ts + "[]", "z.array(" + t.zod + ")"}
+}
+
+// reference refers to an interface or type rendered in a file, and to its
+// schema lazily, so schemas may refer to those declared after them
+func reference(name string) *tsType {
+	return &tsType{name, "z.lazy(() => " + name + "Schema)"}
+}
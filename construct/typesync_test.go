@@ -0,0 +1,132 @@
+package construct
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// syncSource is a models package exercising the mapping of types:sync
+const syncSource = `package models
+
+import "time"
+
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusPublished Status = "published"
+)
+
+type Base struct {
+	ID        uint      ` + "`json:\"id\"`" + `
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+}
+
+type Post struct {
+	Base
+	Title    string            ` + "`json:\"title\"`" + `
+	Status   Status            ` + "`json:\"status\"`" + `
+	Tags     []string          ` + "`json:\"tags\"`" + `
+	Labels   []string          ` + "`json:\"labels,omitempty\"`" + `
+	Meta     map[string]int    ` + "`json:\"meta\"`" + `
+	Parent   *Post             ` + "`json:\"parent\"`" + `
+	Matrix   [][]int           ` + "`json:\"matrix\"`" + `
+	Count    int64             ` + "`json:\"count,string\"`" + `
+	Hidden   string            ` + "`json:\"-\"`" + `
+	internal string
+}
+`
+
+// syncedPost mirrors the Post of syncSource, to check what encoding/json
+// sends for it
+type syncedPost struct {
+	ID     uint             `json:"id"`
+	Tags   []string         `json:"tags"`
+	Labels []string         `json:"labels,omitempty"`
+	Meta   map[string]int   `json:"meta"`
+	Matrix [][]int          `json:"matrix"`
+	Parent *syncedPost      `json:"parent"`
+	Extra  map[string][]int `json:"extra,omitempty"`
+}
+
+func syncTypes(t *testing.T, zod bool) string {
+	t.Helper()
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/app\n\ngo 1.21\n")
+	write("api/models/post.go", syncSource)
+
+	files, err := typeFiles(root, nil, zod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Rel != "vue/app/types/api/models.ts" || !files[0].Derived {
+		t.Fatalf("typeFiles() = %+v, want one derived vue/app/types/api/models.ts", files)
+	}
+	return string(files[0].Content)
+}
+
+func TestTypesSync(t *testing.T) {
+	content := syncTypes(t, false)
+	for _, want := range []string{
+		"export type Status = 'draft' | 'published'\n",
+		"export interface Post {\n  id: number\n  created_at: string\n  title: string\n",
+		"  status: Status\n",
+		"  tags: string[] | null\n",
+		"  labels?: string[]\n",
+		"  meta: Record<string, number> | null\n",
+		"  parent: Post | null\n",
+		"  matrix: (number[] | null)[] | null\n",
+		"  count: string\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("types:sync output has no %q:\n%s", want, content)
+		}
+	}
+	for _, unwanted := range []string{"Hidden", "internal"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("types:sync output has %q:\n%s", unwanted, content)
+		}
+	}
+}
+
+func TestTypesSyncZod(t *testing.T) {
+	content := syncTypes(t, true)
+	for _, want := range []string{
+		"import * as z from 'zod'\n",
+		"export const StatusSchema = z.enum(['draft', 'published'])\n",
+		"export const PostSchema: z.ZodType<Post> = z.object({\n",
+		"  tags: z.array(z.string()).nullable(),\n",
+		"  labels: z.array(z.string()).optional(),\n",
+		"  parent: z.lazy(() => PostSchema).nullable(),\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("types:sync --zod output has no %q:\n%s", want, content)
+		}
+	}
+}
+
+// TestNilSlicesAreNull checks the premise of typing slices and maps as
+// nullable: encoding/json sends null for them when they are nil, unless
+// they are left out with omitempty
+func TestNilSlicesAreNull(t *testing.T) {
+	got, err := json.Marshal(syncedPost{Matrix: [][]int{nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":0,"tags":null,"meta":null,"matrix":[null],"parent":null}`
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
@@ -36,6 +36,17 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
+// removeEmptyDirs removes dir and its parents while they are empty, stopping
+// at stop, which is never removed
+func removeEmptyDirs(dir, stop string) {
+	for dir != stop && strings.HasPrefix(dir, stop+string(os.PathSeparator)) {
+		if os.Remove(dir) != nil {
+			return // Not empty
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
 // detectPackageManager detects which package manager to use
 func detectPackageManager(dir, script string) *exec.Cmd {
 	// Special handling for install command (no "run" prefix needed)
@@ -206,22 +217,27 @@ func Unzip(src, dest string) error {
 	return nil
 }
 
-// pluralize converts a word to plural form (simple implementation)
-func pluralize(word string) string {
-	if strings.HasSuffix(word, "s") || strings.HasSuffix(word, "x") ||
-		strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "sh") {
-		return word + "es"
-	}
-	if strings.HasSuffix(word, "y") {
-		return word[:len(word)-1] + "ies"
-	}
-	return word + "s"
-}
-
 // titleCase converts first letter to uppercase
 func titleCase(s string) string {
 	if len(s) == 0 {
 		return s
 	}
 	return strings.ToUpper(s[:1]) + s[1:]
-}
\ No newline at end of file
+}
+
+// projectModulePath reads the Go module path from the project's go.mod
+func projectModulePath(root string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`), nil
+		}
+	}
+
+	return "", fmt.Errorf("no module directive in go.mod")
+}
@@ -0,0 +1,212 @@
+package construct
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GenerateOptions controls what happens to files that already exist
+type GenerateOptions struct {
+	DryRun       bool // Report what would be written without writing
+	Diff         bool // Print a unified diff of every change (implies DryRun)
+	Force        bool // Overwrite files that differ from the generated output
+	SkipExisting bool // Leave every existing file untouched
+}
+
+// preview reports whether nothing should be written
+func (o GenerateOptions) preview() bool {
+	return o.DryRun || o.Diff
+}
+
+// Actions taken for a generated file
+const (
+	actionCreate   = "create"
+	actionModify   = "modify"
+	actionMerge    = "merge"
+	actionSkip     = "skip"
+	actionConflict = "conflict"
+)
+
+// reasonKeptLocal is why a file is skipped when merging it with the new
+// output changes nothing: it still differs from that output
+const reasonKeptLocal = "kept local edits, differs from the generated output"
+
+// generatedFile is a rendered file waiting to be written
+type generatedFile struct {
+	Path    string // Absolute path
+	Rel     string // Path relative to the project root, for messages
+	Content []byte
+	Patch   bool // Content edits a user-owned file, e.g. api/init.go
+	Derived bool // Content is derived from other sources, e.g. the swagger docs, and always overwrites the file
+
+	Template        string // Template the file was rendered from
+	TemplateVersion string
+
+	current   []byte
+	output    []byte // What will be written, Content merged with local edits
+	action    string
+	reason    string
+	conflicts int
+}
+
+// fileTemplate pairs an output path with the template it is rendered from
+type fileTemplate struct {
+	path     string
+	name     string
+	template string
+	source   string // Where the template was loaded from, for errors
+}
+
+// newGeneratedFile creates a generated file for a path inside root
+func newGeneratedFile(root, path string, content []byte) *generatedFile {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	return &generatedFile{Path: path, Rel: filepath.ToSlash(rel), Content: content}
+}
+
+// planFiles compares each file with what is on disk and decides what to do.
+// Files edited since they were last generated are merged with the new
+// output; files the manifest does not know about are never overwritten
+// unless forced. Derived files are always overwritten, since they must
+// match their sources.
+func planFiles(root string, files []*generatedFile, manifest *Manifest, opts GenerateOptions) error {
+	for _, f := range files {
+		current, err := os.ReadFile(f.Path)
+		f.current, f.output = current, f.Content
+
+		switch {
+		case os.IsNotExist(err):
+			f.action = actionCreate
+		case err != nil:
+			return fmt.Errorf("failed to read %s: %w", f.Rel, err)
+		case bytes.Equal(current, f.Content):
+			f.action, f.reason = actionSkip, "unchanged"
+		case f.Patch || f.Derived || opts.Force:
+			f.action = actionModify
+		case opts.SkipExisting:
+			f.action, f.reason = actionSkip, "exists"
+		case !manifest.modified(f.Rel, current):
+			f.action = actionModify
+		default:
+			base, ok := manifest.base(root, f.Rel)
+			if !ok {
+				f.action, f.reason = actionConflict, "differs from the generated output"
+				break
+			}
+
+			merged, conflicts := merge3(string(base), string(current), string(f.Content))
+			f.output, f.conflicts = []byte(merged), conflicts
+			f.action, f.reason = actionMerge, "kept local changes"
+			if conflicts > 0 {
+				f.reason = fmt.Sprintf("%d conflict(s)", conflicts)
+			}
+			if bytes.Equal(f.output, current) {
+				f.action, f.reason = actionSkip, reasonKeptLocal
+			}
+		}
+	}
+	return nil
+}
+
+// writeFiles writes the generated files according to the options and records
+// them in the manifest. Unless forced, it refuses to write anything when a
+// file it has no record of differs from the generated output, so nothing is
+// written half-way.
+func writeFiles(root string, files []*generatedFile, opts GenerateOptions) error {
+	manifest, err := loadManifest(root)
+	if err != nil {
+		return err
+	}
+	if err := planFiles(root, files, manifest, opts); err != nil {
+		return err
+	}
+
+	if opts.preview() {
+		printPlan(files, opts.Diff)
+		return nil
+	}
+
+	var conflicts []string
+	for _, f := range files {
+		if f.action == actionConflict {
+			conflicts = append(conflicts, "     "+f.Rel)
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%d existing file(s) differ from the generated output:\n%s\n   Review them with --diff, then re-run with --force to overwrite or --skip-existing to keep them",
+			len(conflicts), strings.Join(conflicts, "\n"))
+	}
+
+	for _, f := range files {
+		switch f.action {
+		case actionCreate, actionModify, actionMerge:
+			if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+				return fmt.Errorf("failed to create directory for %s: %w", f.Rel, err)
+			}
+			if err := os.WriteFile(f.Path, f.output, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", f.Rel, err)
+			}
+			switch {
+			case f.action == actionCreate:
+				fmt.Printf("  ✓ Generated %s\n", f.Rel)
+			case f.conflicts > 0:
+				fmt.Printf("  ⚠️  Merged %s with %d conflict(s), resolve the <<<<<<< markers\n", f.Rel, f.conflicts)
+			case f.action == actionMerge:
+				fmt.Printf("  ✓ Updated %s (kept local changes)\n", f.Rel)
+			default:
+				fmt.Printf("  ✓ Updated %s\n", f.Rel)
+			}
+		case actionSkip:
+			if f.reason == reasonKeptLocal {
+				fmt.Printf("  • Kept %s (local edits, differs from the generated output)\n", f.Rel)
+				break
+			}
+			fmt.Printf("  • Skipped %s (%s)\n", f.Rel, f.reason)
+		}
+
+		// Remember the generated output as the base for the next merge,
+		// unless the file was deliberately left alone
+		if !f.Patch && f.reason != "exists" {
+			if err := manifest.record(root, f); err != nil {
+				return fmt.Errorf("failed to record %s in %s: %w", f.Rel, manifestPath, err)
+			}
+		}
+	}
+
+	if err := manifest.save(root); err != nil {
+		return fmt.Errorf("failed to write %s: %w", manifestPath, err)
+	}
+	return nil
+}
+
+// printPlan lists what would happen to each file, optionally with diffs
+func printPlan(files []*generatedFile, diff bool) {
+	for _, f := range files {
+		symbol := map[string]string{
+			actionCreate:   "+",
+			actionModify:   "~",
+			actionMerge:    "~",
+			actionSkip:     "=",
+			actionConflict: "!",
+		}[f.action]
+
+		line := fmt.Sprintf("  %s %-8s  %s", symbol, f.action, f.Rel)
+		if f.reason != "" {
+			line += " (" + f.reason + ")"
+		}
+		fmt.Println(line)
+
+		if diff && f.action != actionSkip {
+			oldName := "a/" + f.Rel
+			if f.action == actionCreate {
+				oldName = "/dev/null"
+			}
+			fmt.Print(unifiedDiff(oldName, "b/"+f.Rel, string(f.current), string(f.output)))
+		}
+	}
+}
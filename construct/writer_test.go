@@ -0,0 +1,160 @@
+package construct
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// planOne writes content once as generated output, replaces it on disk with
+// local, and plans writing regenerated over it
+func planOne(t *testing.T, content, local, regenerated string, derived bool, opts GenerateOptions) *generatedFile {
+	t.Helper()
+	root := t.TempDir()
+	path := filepath.Join(root, "vue", "app", "file.ts")
+	if err := writeFiles(root, []*generatedFile{newGeneratedFile(root, path, []byte(content))}, GenerateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(local), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := loadManifest(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newGeneratedFile(root, path, []byte(regenerated))
+	f.Derived = derived
+	if err := planFiles(root, []*generatedFile{f}, manifest, opts); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestPlanFiles(t *testing.T) {
+	tests := []struct {
+		name                        string
+		content, local, regenerated string
+		derived                     bool
+		opts                        GenerateOptions
+		action, reason, output      string
+	}{
+		{
+			name:        "unchanged",
+			content:     "a\n",
+			local:       "a\n",
+			regenerated: "a\n",
+			action:      actionSkip,
+			reason:      "unchanged",
+			output:      "a\n",
+		},
+		{
+			name:        "not edited",
+			content:     "a\n",
+			local:       "a\n",
+			regenerated: "b\n",
+			action:      actionModify,
+			output:      "b\n",
+		},
+		{
+			name:        "edited and regenerated the same",
+			content:     "a\nb\n",
+			local:       "a\nlocal\nb\n",
+			regenerated: "a\nb\n",
+			action:      actionSkip,
+			reason:      reasonKeptLocal,
+			output:      "a\nlocal\nb\n",
+		},
+		{
+			name:        "edited and regenerated differently",
+			content:     "a\nb\n",
+			local:       "a\nlocal\nb\n",
+			regenerated: "a\nb\nc\n",
+			action:      actionMerge,
+			reason:      "kept local changes",
+			output:      "a\nlocal\nb\nc\n",
+		},
+		{
+			name:        "edited and forced",
+			content:     "a\nb\n",
+			local:       "a\nlocal\nb\n",
+			regenerated: "a\nb\n",
+			opts:        GenerateOptions{Force: true},
+			action:      actionModify,
+			output:      "a\nb\n",
+		},
+		{
+			name:        "derived and edited",
+			content:     "a\nb\n",
+			local:       "a\nlocal\nb\n",
+			regenerated: "a\nb\n",
+			derived:     true,
+			action:      actionModify,
+			output:      "a\nb\n",
+		},
+		{
+			name:        "derived and edited, skipping existing files",
+			content:     "a\nb\n",
+			local:       "a\nlocal\nb\n",
+			regenerated: "a\nb\nc\n",
+			derived:     true,
+			opts:        GenerateOptions{SkipExisting: true},
+			action:      actionModify,
+			output:      "a\nb\nc\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := planOne(t, tt.content, tt.local, tt.regenerated, tt.derived, tt.opts)
+			if f.action != tt.action || f.reason != tt.reason || string(f.output) != tt.output {
+				t.Errorf("planned %s (%s) with %q, want %s (%s) with %q", f.action, f.reason, f.output, tt.action, tt.reason, tt.output)
+			}
+		})
+	}
+}
+
+func TestWriteFilesOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // Content of a file the manifest has no record of, "" for none
+		opts     GenerateOptions
+		fails    bool
+		content  string // Content of the file afterwards, "" when it is not created
+	}{
+		{name: "new file", content: "generated\n"},
+		{name: "new file, dry run", opts: GenerateOptions{DryRun: true}},
+		{name: "new file, diff", opts: GenerateOptions{Diff: true}},
+		{name: "unknown file", existing: "local\n", fails: true, content: "local\n"},
+		{name: "unknown file, dry run", existing: "local\n", opts: GenerateOptions{DryRun: true}, content: "local\n"},
+		{name: "unknown file, diff", existing: "local\n", opts: GenerateOptions{Diff: true}, content: "local\n"},
+		{name: "unknown file, forced", existing: "local\n", opts: GenerateOptions{Force: true}, content: "generated\n"},
+		{name: "unknown file, skipping existing files", existing: "local\n", opts: GenerateOptions{SkipExisting: true}, content: "local\n"},
+		{name: "unknown file, already generated", existing: "generated\n", content: "generated\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			path := filepath.Join(root, "vue", "app", "file.ts")
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := writeFiles(root, []*generatedFile{newGeneratedFile(root, path, []byte("generated\n"))}, tt.opts)
+			if (err != nil) != tt.fails {
+				t.Errorf("writeFiles() = %v, want failure %v", err, tt.fails)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if string(content) != tt.content {
+				t.Errorf("file holds %q, want %q", content, tt.content)
+			}
+		})
+	}
+}